@@ -0,0 +1,83 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package bloom
+
+import (
+	"context"
+	"sync"
+)
+
+var _ BloomFilter = &LocalBloomFilter{}
+
+// LocalBloomFilter 基于内存位数组的布隆过滤器，并发安全
+// 接口中的 ctx 和 error 只是为了和 Redis 实现保持一致，这里永远不会返回 error
+type LocalBloomFilter struct {
+	mutex sync.RWMutex
+	bits  []uint64
+	m     uint64
+	k     uint64
+}
+
+// NewLocalBloomFilter 根据预期的元素数量和误判率创建布隆过滤器
+func NewLocalBloomFilter(expected uint64, falsePositiveRate float64) *LocalBloomFilter {
+	m, k := OptimalParams(expected, falsePositiveRate)
+	return &LocalBloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+func (b *LocalBloomFilter) Add(ctx context.Context, key string) error {
+	return b.AddBatch(ctx, []string{key})
+}
+
+func (b *LocalBloomFilter) AddBatch(ctx context.Context, keys []string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for _, key := range keys {
+		for _, loc := range Locations(key, b.k, b.m) {
+			b.bits[loc/64] |= 1 << (loc % 64)
+		}
+	}
+	return nil
+}
+
+func (b *LocalBloomFilter) MightContain(ctx context.Context, key string) (bool, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return b.test(key), nil
+}
+
+func (b *LocalBloomFilter) MightContainBatch(ctx context.Context, keys []string) ([]bool, error) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	res := make([]bool, len(keys))
+	for i, key := range keys {
+		res[i] = b.test(key)
+	}
+	return res, nil
+}
+
+func (b *LocalBloomFilter) test(key string) bool {
+	for _, loc := range Locations(key, b.k, b.m) {
+		if b.bits[loc/64]&(1<<(loc%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,80 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package bloom
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimalParams(t *testing.T) {
+	testCases := []struct {
+		name  string
+		n     uint64
+		p     float64
+		wantM uint64
+		wantK uint64
+	}{
+		{name: "1%", n: 1000, p: 0.01, wantM: 9586, wantK: 7},
+		{name: "0.1%", n: 1000000, p: 0.001, wantM: 14377588, wantK: 10},
+		{name: "非法误判率", n: 1000, p: 2, wantM: 9586, wantK: 7},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, k := OptimalParams(tc.n, tc.p)
+			assert.Equal(t, tc.wantM, m)
+			assert.Equal(t, tc.wantK, k)
+		})
+	}
+}
+
+func TestLocalBloomFilter(t *testing.T) {
+	const n = 10000
+	b := NewLocalBloomFilter(n, 0.01)
+	ctx := context.Background()
+	keys := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, strconv.Itoa(i))
+	}
+	require.NoError(t, b.Add(ctx, keys[0]))
+	require.NoError(t, b.AddBatch(ctx, keys[1:]))
+
+	// 已经加入的元素一定存在
+	res, err := b.MightContainBatch(ctx, keys)
+	require.NoError(t, err)
+	for _, ok := range res {
+		assert.True(t, ok)
+	}
+	ok, err := b.MightContain(ctx, keys[0])
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	// 误判率在预期附近
+	falsePositive := 0
+	for i := n; i < 2*n; i++ {
+		ok, err = b.MightContain(ctx, strconv.Itoa(i))
+		require.NoError(t, err)
+		if ok {
+			falsePositive++
+		}
+	}
+	assert.Less(t, float64(falsePositive)/n, 0.02)
+}
@@ -0,0 +1,70 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package bloom
+
+import (
+	"context"
+	"hash/fnv"
+	"math"
+)
+
+// BloomFilter 布隆过滤器
+// MightContain 返回 false 时元素一定不存在，返回 true 时元素可能存在
+type BloomFilter interface {
+	Add(ctx context.Context, key string) error
+	AddBatch(ctx context.Context, keys []string) error
+	MightContain(ctx context.Context, key string) (bool, error)
+	// MightContainBatch 返回的结果和 keys 一一对应
+	MightContainBatch(ctx context.Context, keys []string) ([]bool, error)
+}
+
+// OptimalParams 根据预期的元素数量 n 和误判率 p 计算位数组大小 m 和哈希函数个数 k
+// m = -n * ln(p) / (ln2)^2
+// k = m / n * ln2
+func OptimalParams(n uint64, p float64) (m uint64, k uint64) {
+	if n == 0 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		p = 0.01
+	}
+	m = uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return m, k
+}
+
+// Locations 返回 key 在大小为 m 的位数组中对应的 k 个位置
+// 使用 FNV-1a 的结果做双重哈希：h(i) = h1 + i * h2
+// 本地实现和 Redis 实现共用，保证同样的参数下位置一致
+func Locations(key string, k uint64, m uint64) []uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	sum := h.Sum64()
+	h1, h2 := sum&math.MaxUint32, sum>>32
+	// h2 为0时所有位置都相同，退化成一个哈希函数
+	if h2 == 0 {
+		h2 = 1
+	}
+	res := make([]uint64, k)
+	for i := uint64(0); i < k; i++ {
+		res[i] = (h1 + i*h2) % m
+	}
+	return res
+}
@@ -0,0 +1,183 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+var _ Cache[string, any] = &LFU[string, any]{}
+
+// LFU 最不经常使用淘汰的缓存，访问频率相同时淘汰最久没有访问的
+type LFU[K comparable, V any] struct {
+	mutex    sync.Mutex
+	capacity int
+	opts     options[K, V]
+	items    map[K]*list.Element
+	// 访问频率 => 该频率下的元素，越靠近队头越是最近访问的
+	freqs   map[int]*list.List
+	minFreq int
+	stats   Stats
+	now     func() time.Time
+}
+
+// NewLFU 创建 LFU 缓存，capacity 必须大于0
+func NewLFU[K comparable, V any](capacity int, opts ...Option[K, V]) *LFU[K, V] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LFU[K, V]{
+		capacity: capacity,
+		opts:     newOptions[K, V](opts),
+		items:    make(map[K]*list.Element, capacity),
+		freqs:    make(map[int]*list.List),
+		now:      time.Now,
+	}
+}
+
+func (c *LFU[K, V]) Get(key K) (V, bool) {
+	c.mutex.Lock()
+	elem, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		c.mutex.Unlock()
+		var v V
+		return v, false
+	}
+	e := elem.Value.(*entry[K, V])
+	if e.expired(c.now()) {
+		c.removeElement(elem)
+		c.stats.Misses++
+		c.stats.Evictions++
+		c.mutex.Unlock()
+		notify(c.opts.onEvict, []*entry[K, V]{e})
+		var v V
+		return v, false
+	}
+	c.increment(elem)
+	c.stats.Hits++
+	c.mutex.Unlock()
+	return e.val, true
+}
+
+func (c *LFU[K, V]) Set(key K, val V) {
+	c.SetWithTTL(key, val, c.opts.ttl)
+}
+
+func (c *LFU[K, V]) SetWithTTL(key K, val V, ttl time.Duration) {
+	c.mutex.Lock()
+	now := c.now()
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.val = val
+		e.expireAt = expireAt(now, ttl)
+		c.increment(elem)
+		c.mutex.Unlock()
+		return
+	}
+	var evicted []*entry[K, V]
+	for len(c.items) >= c.capacity {
+		evicted = append(evicted, c.removeElement(c.victim()))
+		c.stats.Evictions++
+	}
+	e := &entry[K, V]{
+		key:      key,
+		val:      val,
+		expireAt: expireAt(now, ttl),
+		freq:     1,
+	}
+	c.items[key] = c.listOf(e.freq).PushFront(e)
+	c.minFreq = 1
+	c.mutex.Unlock()
+	notify(c.opts.onEvict, evicted)
+}
+
+func (c *LFU[K, V]) Delete(key K) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.removeElement(elem)
+	return true
+}
+
+func (c *LFU[K, V]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.items)
+}
+
+func (c *LFU[K, V]) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stats
+}
+
+func (c *LFU[K, V]) listOf(freq int) *list.List {
+	l, ok := c.freqs[freq]
+	if !ok {
+		l = list.New()
+		c.freqs[freq] = l
+	}
+	return l
+}
+
+// victim 返回要淘汰的元素，调用方需要保证缓存不为空
+func (c *LFU[K, V]) victim() *list.Element {
+	l, ok := c.freqs[c.minFreq]
+	if !ok {
+		// Delete 或者过期清理之后 minFreq 可能已经失效，重新计算
+		c.minFreq = 0
+		for freq := range c.freqs {
+			if c.minFreq == 0 || freq < c.minFreq {
+				c.minFreq = freq
+			}
+		}
+		l = c.freqs[c.minFreq]
+	}
+	return l.Back()
+}
+
+func (c *LFU[K, V]) increment(elem *list.Element) {
+	e := c.detach(elem)
+	if _, ok := c.freqs[c.minFreq]; !ok && c.minFreq == e.freq {
+		c.minFreq++
+	}
+	e.freq++
+	c.items[e.key] = c.listOf(e.freq).PushFront(e)
+}
+
+func (c *LFU[K, V]) removeElement(elem *list.Element) *entry[K, V] {
+	e := c.detach(elem)
+	delete(c.items, e.key)
+	return e
+}
+
+// detach 把元素从所在的频率链表中摘下来
+func (c *LFU[K, V]) detach(elem *list.Element) *entry[K, V] {
+	e := elem.Value.(*entry[K, V])
+	l := c.freqs[e.freq]
+	l.Remove(elem)
+	if l.Len() == 0 {
+		delete(c.freqs, e.freq)
+	}
+	return e
+}
@@ -0,0 +1,110 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLFU_Evict(t *testing.T) {
+	testCases := []struct {
+		name        string
+		capacity    int
+		set         []int
+		get         []int
+		wantEvicted []int
+	}{
+		{
+			name:        "淘汰访问次数最少的",
+			capacity:    3,
+			set:         []int{1, 2, 3},
+			get:         []int{1, 1, 2, 3},
+			wantEvicted: []int{2},
+		},
+		{
+			name:        "访问次数相同时淘汰最久没有访问的",
+			capacity:    3,
+			set:         []int{1, 2, 3},
+			get:         []int{3, 2, 1},
+			wantEvicted: []int{3},
+		},
+		{
+			name:        "没有访问过时淘汰最早写入的",
+			capacity:    3,
+			set:         []int{1, 2, 3},
+			wantEvicted: []int{1},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var evicted []int
+			c := NewLFU[int, int](tc.capacity, WithOnEvict[int, int](func(key int, val int) {
+				evicted = append(evicted, key)
+			}))
+			for _, k := range tc.set {
+				c.Set(k, k)
+			}
+			for _, k := range tc.get {
+				_, ok := c.Get(k)
+				assert.True(t, ok)
+			}
+			c.Set(100, 100)
+			assert.Equal(t, tc.wantEvicted, evicted)
+			assert.Equal(t, tc.capacity, c.Len())
+			_, ok := c.Get(100)
+			assert.True(t, ok)
+		})
+	}
+}
+
+func TestLFU_DeleteThenEvict(t *testing.T) {
+	c := NewLFU[int, int](2)
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Get(2)
+	c.Get(2)
+	// 删除之后 minFreq 失效，需要重新计算
+	assert.True(t, c.Delete(1))
+	c.Set(3, 3)
+	c.Get(3)
+	c.Set(4, 4)
+	_, ok := c.Get(2)
+	assert.True(t, ok)
+	_, ok = c.Get(4)
+	assert.True(t, ok)
+	_, ok = c.Get(3)
+	assert.False(t, ok)
+}
+
+func TestLFU_TTL(t *testing.T) {
+	now := time.Now()
+	c := NewLFU[int, int](10, WithTTL[int, int](time.Minute))
+	c.now = func() time.Time { return now }
+	c.Set(1, 1)
+	c.SetWithTTL(2, 2, time.Hour)
+	now = now.Add(2 * time.Minute)
+	_, ok := c.Get(1)
+	assert.False(t, ok)
+	v, ok := c.Get(2)
+	assert.True(t, ok)
+	assert.Equal(t, 2, v)
+	assert.Equal(t, 1, c.Len())
+	assert.Equal(t, Stats{Hits: 1, Misses: 1, Evictions: 1}, c.Stats())
+}
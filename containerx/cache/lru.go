@@ -0,0 +1,134 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+var _ Cache[string, any] = &LRU[string, any]{}
+
+// LRU 最近最少使用淘汰的缓存
+type LRU[K comparable, V any] struct {
+	mutex    sync.Mutex
+	capacity int
+	opts     options[K, V]
+	// 越靠近队头越是最近访问的
+	ll    *list.List
+	items map[K]*list.Element
+	stats Stats
+	now   func() time.Time
+}
+
+// NewLRU 创建 LRU 缓存，capacity 必须大于0
+func NewLRU[K comparable, V any](capacity int, opts ...Option[K, V]) *LRU[K, V] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRU[K, V]{
+		capacity: capacity,
+		opts:     newOptions[K, V](opts),
+		ll:       list.New(),
+		items:    make(map[K]*list.Element, capacity),
+		now:      time.Now,
+	}
+}
+
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mutex.Lock()
+	elem, ok := c.items[key]
+	if !ok {
+		c.stats.Misses++
+		c.mutex.Unlock()
+		var v V
+		return v, false
+	}
+	e := elem.Value.(*entry[K, V])
+	if e.expired(c.now()) {
+		c.removeElement(elem)
+		c.stats.Misses++
+		c.stats.Evictions++
+		c.mutex.Unlock()
+		notify(c.opts.onEvict, []*entry[K, V]{e})
+		var v V
+		return v, false
+	}
+	c.ll.MoveToFront(elem)
+	c.stats.Hits++
+	c.mutex.Unlock()
+	return e.val, true
+}
+
+func (c *LRU[K, V]) Set(key K, val V) {
+	c.SetWithTTL(key, val, c.opts.ttl)
+}
+
+func (c *LRU[K, V]) SetWithTTL(key K, val V, ttl time.Duration) {
+	c.mutex.Lock()
+	now := c.now()
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.val = val
+		e.expireAt = expireAt(now, ttl)
+		c.ll.MoveToFront(elem)
+		c.mutex.Unlock()
+		return
+	}
+	var evicted []*entry[K, V]
+	for c.ll.Len() >= c.capacity {
+		evicted = append(evicted, c.removeElement(c.ll.Back()))
+		c.stats.Evictions++
+	}
+	c.items[key] = c.ll.PushFront(&entry[K, V]{
+		key:      key,
+		val:      val,
+		expireAt: expireAt(now, ttl),
+	})
+	c.mutex.Unlock()
+	notify(c.opts.onEvict, evicted)
+}
+
+func (c *LRU[K, V]) Delete(key K) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	elem, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.removeElement(elem)
+	return true
+}
+
+func (c *LRU[K, V]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.ll.Len()
+}
+
+func (c *LRU[K, V]) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.stats
+}
+
+func (c *LRU[K, V]) removeElement(elem *list.Element) *entry[K, V] {
+	e := c.ll.Remove(elem).(*entry[K, V])
+	delete(c.items, e.key)
+	return e
+}
@@ -0,0 +1,135 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cache
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_Evict(t *testing.T) {
+	testCases := []struct {
+		name        string
+		capacity    int
+		set         []int
+		get         []int
+		wantKeys    []int
+		wantEvicted []int
+	}{
+		{
+			name:     "容量足够",
+			capacity: 3,
+			set:      []int{1, 2, 3},
+			wantKeys: []int{1, 2, 3},
+		},
+		{
+			name:        "淘汰最久没有访问的",
+			capacity:    3,
+			set:         []int{1, 2, 3, 4},
+			wantKeys:    []int{2, 3, 4},
+			wantEvicted: []int{1},
+		},
+		{
+			name:        "访问之后不会被淘汰",
+			capacity:    3,
+			set:         []int{1, 2, 3},
+			get:         []int{1},
+			wantKeys:    []int{1, 3},
+			wantEvicted: []int{2},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var evicted []int
+			c := NewLRU[int, int](tc.capacity, WithOnEvict[int, int](func(key int, val int) {
+				evicted = append(evicted, key)
+			}))
+			for _, k := range tc.set {
+				c.Set(k, k)
+			}
+			for _, k := range tc.get {
+				_, ok := c.Get(k)
+				assert.True(t, ok)
+			}
+			if len(tc.get) > 0 {
+				c.Set(100, 100)
+			}
+			for _, k := range tc.wantKeys {
+				v, ok := c.Get(k)
+				assert.True(t, ok)
+				assert.Equal(t, k, v)
+			}
+			assert.Equal(t, tc.wantEvicted, evicted)
+		})
+	}
+}
+
+func TestLRU_TTL(t *testing.T) {
+	now := time.Now()
+	var evicted []int
+	c := NewLRU[int, int](10, WithTTL[int, int](time.Minute), WithOnEvict[int, int](func(key int, val int) {
+		evicted = append(evicted, key)
+	}))
+	c.now = func() time.Time { return now }
+	c.Set(1, 1)
+	c.SetWithTTL(2, 2, time.Hour)
+	c.SetWithTTL(3, 3, 0)
+
+	now = now.Add(2 * time.Minute)
+	_, ok := c.Get(1)
+	assert.False(t, ok)
+	_, ok = c.Get(2)
+	assert.True(t, ok)
+
+	now = now.Add(24 * time.Hour)
+	_, ok = c.Get(2)
+	assert.False(t, ok)
+	_, ok = c.Get(3)
+	assert.True(t, ok)
+
+	assert.Equal(t, []int{1, 2}, evicted)
+	assert.Equal(t, Stats{Hits: 2, Misses: 2, Evictions: 2}, c.Stats())
+	assert.Equal(t, 0.5, c.Stats().HitRate())
+}
+
+func TestLRU_Delete(t *testing.T) {
+	c := NewLRU[int, int](10)
+	c.Set(1, 1)
+	assert.True(t, c.Delete(1))
+	assert.False(t, c.Delete(1))
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRU_Concurrent(t *testing.T) {
+	c := NewLRU[int, int](100)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(base int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Set(base*1000+j, j)
+				c.Get(j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 100, c.Len())
+}
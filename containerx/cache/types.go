@@ -0,0 +1,113 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package cache
+
+import "time"
+
+// Cache 本地缓存，所有实现都是并发安全的
+type Cache[K comparable, V any] interface {
+	// Get 获取缓存，key 不存在或者已经过期时第二个返回值为 false
+	Get(key K) (V, bool)
+	// Set 使用默认过期时间写入缓存
+	Set(key K, val V)
+	// SetWithTTL 使用指定的过期时间写入缓存，ttl <= 0 表示永不过期
+	SetWithTTL(key K, val V, ttl time.Duration)
+	// Delete 删除缓存，返回 key 是否存在，不会触发 OnEvict
+	Delete(key K) bool
+	// Len 返回缓存中元素的数量，可能包含已过期但还没有被清理的元素
+	Len() int
+	// Stats 返回命中统计
+	Stats() Stats
+}
+
+// Stats 缓存命中统计
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+}
+
+// HitRate 命中率，没有访问过时返回0
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// EvictCallback 元素因为容量不足被淘汰或者过期被清理时的回调
+// 回调在释放锁之后执行，所以可以在回调中访问缓存
+type EvictCallback[K comparable, V any] func(key K, val V)
+
+type Option[K comparable, V any] func(o *options[K, V])
+
+type options[K comparable, V any] struct {
+	ttl     time.Duration
+	onEvict EvictCallback[K, V]
+}
+
+// WithTTL 设置 Set 使用的默认过期时间，默认永不过期
+func WithTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.ttl = ttl
+	}
+}
+
+// WithOnEvict 设置淘汰回调
+func WithOnEvict[K comparable, V any](fn EvictCallback[K, V]) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.onEvict = fn
+	}
+}
+
+func newOptions[K comparable, V any](opts []Option[K, V]) options[K, V] {
+	o := options[K, V]{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+type entry[K comparable, V any] struct {
+	key K
+	val V
+	// 零值表示永不过期
+	expireAt time.Time
+	// 访问频率，只有 LFU 使用
+	freq int
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expireAt.IsZero() && !now.Before(e.expireAt)
+}
+
+func expireAt(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+func notify[K comparable, V any](fn EvictCallback[K, V], evicted []*entry[K, V]) {
+	if fn == nil {
+		return
+	}
+	for _, e := range evicted {
+		fn(e.key, e.val)
+	}
+}
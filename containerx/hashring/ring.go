@@ -0,0 +1,208 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package hashring
+
+import (
+	"fmt"
+	"hash/crc32"
+	"sort"
+	"sync"
+)
+
+// HashFunc 哈希函数
+type HashFunc func(data []byte) uint32
+
+type Option func(o *options)
+
+type options struct {
+	replicas int
+	hash     HashFunc
+}
+
+// WithReplicas 设置每个节点的虚拟节点数量，默认160
+func WithReplicas(replicas int) Option {
+	return func(o *options) {
+		o.replicas = replicas
+	}
+}
+
+// WithHashFunc 设置哈希函数，默认 crc32.ChecksumIEEE
+func WithHashFunc(hash HashFunc) Option {
+	return func(o *options) {
+		o.hash = hash
+	}
+}
+
+// Ring 一致性哈希环，并发安全
+// 读多写少，节点变更时持有写锁重建哈希环，查询时只持有读锁
+type Ring[N comparable] struct {
+	mutex    sync.RWMutex
+	replicas int
+	hash     HashFunc
+	// 有序的虚拟节点哈希值
+	hashes []uint32
+	// 虚拟节点哈希值 => 真实节点
+	virtual map[uint32]N
+	nodes   map[N]struct{}
+}
+
+func NewRing[N comparable](opts ...Option) *Ring[N] {
+	o := options{
+		replicas: 160,
+		hash:     crc32.ChecksumIEEE,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.replicas < 1 {
+		o.replicas = 1
+	}
+	return &Ring[N]{
+		replicas: o.replicas,
+		hash:     o.hash,
+		virtual:  make(map[uint32]N),
+		nodes:    make(map[N]struct{}),
+	}
+}
+
+// Add 添加节点，已经存在的节点会被忽略
+// 不同虚拟节点的哈希值冲突时，名字（fmt.Sprint 的结果）更小的节点生效，和加入的顺序无关
+// 这样成员相同的多个进程总是把同一个 key 路由到同一个节点
+func (r *Ring[N]) Add(nodes ...N) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, node := range nodes {
+		if _, ok := r.nodes[node]; ok {
+			continue
+		}
+		r.nodes[node] = struct{}{}
+		r.addVirtual(node)
+	}
+	sort.Slice(r.hashes, func(i, j int) bool {
+		return r.hashes[i] < r.hashes[j]
+	})
+}
+
+// addVirtual 加入 node 的所有虚拟节点，调用方需要持有写锁并在之后重新排序
+func (r *Ring[N]) addVirtual(node N) {
+	for i := 0; i < r.replicas; i++ {
+		h := r.virtualHash(node, i)
+		if owner, ok := r.virtual[h]; ok {
+			if owner != node && fmt.Sprint(node) < fmt.Sprint(owner) {
+				r.virtual[h] = node
+			}
+			continue
+		}
+		r.virtual[h] = node
+		r.hashes = append(r.hashes, h)
+	}
+}
+
+// Remove 删除节点
+// 被删除节点占用的哈希值可能和其他节点冲突过，所以用剩下的节点重建哈希环，让之前被覆盖的虚拟节点重新生效
+func (r *Ring[N]) Remove(nodes ...N) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	removed := false
+	for _, node := range nodes {
+		if _, ok := r.nodes[node]; !ok {
+			continue
+		}
+		delete(r.nodes, node)
+		removed = true
+	}
+	if !removed {
+		return
+	}
+	r.virtual = make(map[uint32]N, len(r.nodes)*r.replicas)
+	r.hashes = r.hashes[:0]
+	for node := range r.nodes {
+		r.addVirtual(node)
+	}
+	sort.Slice(r.hashes, func(i, j int) bool {
+		return r.hashes[i] < r.hashes[j]
+	})
+}
+
+// Get 返回 key 对应的节点，没有节点时第二个返回值为 false
+func (r *Ring[N]) Get(key string) (N, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if len(r.hashes) == 0 {
+		var n N
+		return n, false
+	}
+	return r.virtual[r.hashes[r.search(key)]], true
+}
+
+// GetN 沿着哈希环顺时针返回 key 对应的 n 个不同节点，可以用于副本选择
+// 节点数量不足 n 时返回所有节点
+func (r *Ring[N]) GetN(key string, n int) []N {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if n > len(r.nodes) {
+		n = len(r.nodes)
+	}
+	res := make([]N, 0, n)
+	if n <= 0 {
+		return res
+	}
+	seen := make(map[N]struct{}, n)
+	start := r.search(key)
+	for i := 0; i < len(r.hashes) && len(res) < n; i++ {
+		node := r.virtual[r.hashes[(start+i)%len(r.hashes)]]
+		if _, ok := seen[node]; ok {
+			continue
+		}
+		seen[node] = struct{}{}
+		res = append(res, node)
+	}
+	return res
+}
+
+// Nodes 返回所有真实节点，顺序不固定
+func (r *Ring[N]) Nodes() []N {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	res := make([]N, 0, len(r.nodes))
+	for node := range r.nodes {
+		res = append(res, node)
+	}
+	return res
+}
+
+func (r *Ring[N]) Len() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return len(r.nodes)
+}
+
+// search 返回第一个大于等于 key 哈希值的虚拟节点下标，超过最大值时回到环的起点
+func (r *Ring[N]) search(key string) int {
+	h := r.hash([]byte(key))
+	idx := sort.Search(len(r.hashes), func(i int) bool {
+		return r.hashes[i] >= h
+	})
+	if idx == len(r.hashes) {
+		idx = 0
+	}
+	return idx
+}
+
+func (r *Ring[N]) virtualHash(node N, i int) uint32 {
+	return r.hash([]byte(fmt.Sprintf("%v#%d", node, i)))
+}
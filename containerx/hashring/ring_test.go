@@ -0,0 +1,166 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package hashring
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 使用数字本身作为哈希值，方便构造确定的哈希环
+// 节点 "2" 的虚拟节点为 "2#0"、"2#1"、"2#2"，去掉 "#" 之后哈希值为 20、21、22
+func numberHash(data []byte) uint32 {
+	s := make([]byte, 0, len(data))
+	for _, b := range data {
+		if b != '#' {
+			s = append(s, b)
+		}
+	}
+	n, _ := strconv.Atoi(string(s))
+	return uint32(n)
+}
+
+func TestRing_Get(t *testing.T) {
+	r := NewRing[string](WithReplicas(3), WithHashFunc(numberHash))
+	_, ok := r.Get("1")
+	assert.False(t, ok)
+
+	// 虚拟节点：20 21 22 40 41 42 60 61 62
+	r.Add("2", "4", "6")
+	testCases := map[string]string{
+		"2":  "2",
+		"11": "2",
+		"23": "4",
+		"27": "4",
+		"42": "4",
+		"43": "6",
+		"63": "2",
+	}
+	for key, want := range testCases {
+		node, ok := r.Get(key)
+		assert.True(t, ok)
+		assert.Equal(t, want, node, key)
+	}
+
+	// 加入节点之后，只有一部分 key 重新映射
+	r.Add("8")
+	testCases["27"] = "4"
+	testCases["63"] = "8"
+	for key, want := range testCases {
+		node, _ := r.Get(key)
+		assert.Equal(t, want, node, key)
+	}
+
+	r.Remove("4")
+	node, _ := r.Get("27")
+	assert.Equal(t, "6", node)
+	assert.Equal(t, 3, r.Len())
+	assert.ElementsMatch(t, []string{"2", "6", "8"}, r.Nodes())
+}
+
+func TestRing_GetN(t *testing.T) {
+	r := NewRing[string](WithReplicas(3), WithHashFunc(numberHash))
+	r.Add("2", "4", "6")
+	testCases := []struct {
+		name string
+		key  string
+		n    int
+		want []string
+	}{
+		{name: "一个", key: "23", n: 1, want: []string{"4"}},
+		{name: "去重", key: "23", n: 2, want: []string{"4", "6"}},
+		{name: "绕回起点", key: "61", n: 3, want: []string{"6", "2", "4"}},
+		{name: "超过节点数量", key: "61", n: 10, want: []string{"6", "2", "4"}},
+		{name: "非法数量", key: "61", n: 0, want: []string{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, r.GetN(tc.key, tc.n))
+		})
+	}
+}
+
+func TestRing_Collision(t *testing.T) {
+	// 11 个虚拟节点时，"1#10" 和 "11#0" 的哈希值都是 110
+	newRing := func(nodes ...string) *Ring[string] {
+		r := NewRing[string](WithReplicas(11), WithHashFunc(numberHash))
+		r.Add(nodes...)
+		return r
+	}
+	a, b := newRing("1", "11", "2"), newRing("2", "11", "1")
+	// 路由和加入的顺序无关，冲突时名字更小的节点生效
+	for i := 0; i < 2000; i++ {
+		key := strconv.Itoa(i)
+		nodeA, _ := a.Get(key)
+		nodeB, _ := b.Get(key)
+		assert.Equal(t, nodeA, nodeB, key)
+	}
+	node, _ := a.Get("110")
+	assert.Equal(t, "1", node)
+
+	// 删除之后被覆盖的虚拟节点重新生效
+	a.Remove("1")
+	node, _ = a.Get("110")
+	assert.Equal(t, "11", node)
+	c := newRing("11", "2")
+	for i := 0; i < 2000; i++ {
+		key := strconv.Itoa(i)
+		nodeA, _ := a.Get(key)
+		nodeC, _ := c.Get(key)
+		assert.Equal(t, nodeC, nodeA, key)
+	}
+}
+
+func TestRing_Distribution(t *testing.T) {
+	r := NewRing[int]()
+	r.Add(1, 2, 3, 4)
+	cnt := make(map[int]int, 4)
+	for i := 0; i < 100000; i++ {
+		node, _ := r.Get("user:" + strconv.Itoa(i))
+		cnt[node]++
+	}
+	for _, c := range cnt {
+		// 每个节点大致分到 1/4
+		assert.InDelta(t, 25000, c, 5000)
+	}
+}
+
+func TestRing_Concurrent(t *testing.T) {
+	r := NewRing[int]()
+	r.Add(1, 2, 3)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if i == 0 {
+					r.Add(j + 10)
+					r.Remove(j + 10)
+					continue
+				}
+				_, ok := r.Get(strconv.Itoa(j))
+				assert.True(t, ok)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 3, r.Len())
+}
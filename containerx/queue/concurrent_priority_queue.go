@@ -0,0 +1,106 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wkRonin/toolkit/containerx"
+)
+
+// ConcurrentPriorityQueue 并发安全的阻塞优先队列
+// 有界队列满的时候 Enqueue 会阻塞，队列为空的时候 Dequeue 会阻塞，直到 ctx 超时或者被取消
+type ConcurrentPriorityQueue[T any] struct {
+	pq       *PriorityQueue[T]
+	mutex    *sync.Mutex
+	notEmpty *cond
+	notFull  *cond
+}
+
+// NewConcurrentPriorityQueue 创建并发安全的优先队列 capacity <= 0 时，为无界队列，否则为有界队列
+func NewConcurrentPriorityQueue[T any](capacity int, compare containerx.Comparator[T], opts ...Option) *ConcurrentPriorityQueue[T] {
+	mutex := &sync.Mutex{}
+	return &ConcurrentPriorityQueue[T]{
+		pq:       NewPriorityQueue[T](capacity, compare, opts...),
+		mutex:    mutex,
+		notEmpty: newCond(mutex),
+		notFull:  newCond(mutex),
+	}
+}
+
+func (c *ConcurrentPriorityQueue[T]) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.pq.Len()
+}
+
+// Cap 无界队列返回0，有界队列返回创建队列时设置的值
+func (c *ConcurrentPriorityQueue[T]) Cap() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.pq.Cap()
+}
+
+// Peek 不会阻塞，队列为空时返回 ErrEmptyQueue
+func (c *ConcurrentPriorityQueue[T]) Peek() (T, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.pq.Peek()
+}
+
+// Enqueue 入队，有界队列满的时候阻塞直到有空位或者 ctx 结束
+func (c *ConcurrentPriorityQueue[T]) Enqueue(ctx context.Context, t T) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for c.pq.isFull() {
+		if err := c.notFull.wait(ctx); err != nil {
+			return err
+		}
+	}
+	err := c.pq.Enqueue(t)
+	if err != nil {
+		return err
+	}
+	c.notEmpty.broadcast()
+	return nil
+}
+
+// Dequeue 出队，队列为空的时候阻塞直到有元素或者 ctx 结束
+func (c *ConcurrentPriorityQueue[T]) Dequeue(ctx context.Context) (T, error) {
+	if ctx.Err() != nil {
+		var t T
+		return t, ctx.Err()
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for c.pq.isEmpty() {
+		if err := c.notEmpty.wait(ctx); err != nil {
+			var t T
+			return t, err
+		}
+	}
+	t, err := c.pq.Dequeue()
+	if err != nil {
+		return t, err
+	}
+	c.notFull.broadcast()
+	return t, nil
+}
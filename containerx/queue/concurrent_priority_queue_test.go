@@ -0,0 +1,189 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentPriorityQueue_Enqueue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		capacity int
+		data     []int
+		timeout  time.Duration
+		element  int
+		wantErr  error
+		wantLen  int
+	}{
+		{
+			name:     "有界空队列",
+			capacity: 10,
+			data:     []int{},
+			timeout:  time.Second,
+			element:  10,
+			wantLen:  1,
+		},
+		{
+			name:     "有界满队列超时",
+			capacity: 6,
+			data:     []int{6, 5, 4, 3, 2, 1},
+			timeout:  100 * time.Millisecond,
+			element:  10,
+			wantErr:  context.DeadlineExceeded,
+			wantLen:  6,
+		},
+		{
+			name:     "无界非空队列",
+			capacity: 0,
+			data:     []int{6, 5, 4, 3, 2, 1},
+			timeout:  time.Second,
+			element:  10,
+			wantLen:  7,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := concurrentPriorityQueueOf(t, tc.capacity, tc.data)
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+			err := q.Enqueue(ctx, tc.element)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantLen, q.Len())
+			assert.Equal(t, tc.capacity, q.Cap())
+		})
+	}
+}
+
+func TestConcurrentPriorityQueue_Dequeue(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    []int
+		timeout time.Duration
+		wantErr error
+		wantVal int
+	}{
+		{
+			name:    "空队列超时",
+			data:    []int{},
+			timeout: 100 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name:    "非空队列",
+			data:    []int{6, 5, 4, 3, 2, 1},
+			timeout: time.Second,
+			wantVal: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := concurrentPriorityQueueOf(t, 0, tc.data)
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+			val, err := q.Dequeue(ctx)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantVal, val)
+		})
+	}
+}
+
+func TestConcurrentPriorityQueue_Block(t *testing.T) {
+	t.Run("队列满时入队阻塞到出队", func(t *testing.T) {
+		q := concurrentPriorityQueueOf(t, 2, []int{2, 1})
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = q.Dequeue(context.Background())
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, q.Enqueue(ctx, 3))
+		assert.Equal(t, 2, q.Len())
+	})
+	t.Run("队列空时出队阻塞到入队", func(t *testing.T) {
+		q := concurrentPriorityQueueOf(t, 0, []int{})
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_ = q.Enqueue(context.Background(), 10)
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		val, err := q.Dequeue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 10, val)
+	})
+}
+
+func TestConcurrentPriorityQueue_Concurrent(t *testing.T) {
+	const producers, perProducer = 10, 100
+	q := NewConcurrentPriorityQueue[int](16, compare())
+	var wg sync.WaitGroup
+	for i := 0; i < producers; i++ {
+		wg.Add(1)
+		go func(base int) {
+			defer wg.Done()
+			for j := 0; j < perProducer; j++ {
+				_ = q.Enqueue(context.Background(), base*perProducer+j)
+			}
+		}(i)
+	}
+	res := make([]int, 0, producers*perProducer)
+	var mutex sync.Mutex
+	var cwg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		cwg.Add(1)
+		go func() {
+			defer cwg.Done()
+			for {
+				ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+				val, err := q.Dequeue(ctx)
+				cancel()
+				if err != nil {
+					return
+				}
+				mutex.Lock()
+				res = append(res, val)
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	cwg.Wait()
+	require.Len(t, res, producers*perProducer)
+	sort.Ints(res)
+	for i, val := range res {
+		assert.Equal(t, i, val)
+	}
+}
+
+func concurrentPriorityQueueOf(t *testing.T, capacity int, data []int) *ConcurrentPriorityQueue[int] {
+	q := NewConcurrentPriorityQueue[int](capacity, compare())
+	for _, el := range data {
+		require.NoError(t, q.Enqueue(context.Background(), el))
+	}
+	return q
+}
@@ -0,0 +1,94 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import "sync/atomic"
+
+// ConcurrentQueue 无锁的无界先进先出队列，支持多生产者多消费者
+// 使用 Michael-Scott 算法实现，依赖 GC 避免 ABA 问题
+type ConcurrentQueue[T any] struct {
+	// head 指向哨兵节点，head.next 才是队头元素
+	head   atomic.Pointer[linkedNode[T]]
+	tail   atomic.Pointer[linkedNode[T]]
+	length atomic.Int64
+}
+
+type linkedNode[T any] struct {
+	val  T
+	next atomic.Pointer[linkedNode[T]]
+}
+
+func NewConcurrentQueue[T any]() *ConcurrentQueue[T] {
+	q := &ConcurrentQueue[T]{}
+	dummy := &linkedNode[T]{}
+	q.head.Store(dummy)
+	q.tail.Store(dummy)
+	return q
+}
+
+// Len 并发场景下只是一个近似值
+func (q *ConcurrentQueue[T]) Len() int {
+	return int(q.length.Load())
+}
+
+func (q *ConcurrentQueue[T]) Enqueue(t T) {
+	n := &linkedNode[T]{val: t}
+	for {
+		tail := q.tail.Load()
+		next := tail.next.Load()
+		if tail != q.tail.Load() {
+			continue
+		}
+		if next != nil {
+			// tail 落后了，帮忙推进
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, n) {
+			// 推进失败也没关系，其他 goroutine 会帮忙推进
+			q.tail.CompareAndSwap(tail, n)
+			q.length.Add(1)
+			return
+		}
+	}
+}
+
+// Dequeue 队列为空时返回 ErrEmptyQueue，不会阻塞
+func (q *ConcurrentQueue[T]) Dequeue() (T, error) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		next := head.next.Load()
+		if head != q.head.Load() {
+			continue
+		}
+		if head == tail {
+			if next == nil {
+				var t T
+				return t, ErrEmptyQueue
+			}
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		// 必须在 CAS 之前读取，CAS 成功之后 next 成为新的哨兵节点
+		val := next.val
+		if q.head.CompareAndSwap(head, next) {
+			q.length.Add(-1)
+			return val, nil
+		}
+	}
+}
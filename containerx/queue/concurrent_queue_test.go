@@ -0,0 +1,145 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentQueue(t *testing.T) {
+	q := NewConcurrentQueue[int]()
+	_, err := q.Dequeue()
+	assert.Equal(t, ErrEmptyQueue, err)
+	for i := 0; i < 5; i++ {
+		q.Enqueue(i)
+	}
+	assert.Equal(t, 5, q.Len())
+	for i := 0; i < 5; i++ {
+		val, err := q.Dequeue()
+		require.NoError(t, err)
+		assert.Equal(t, i, val)
+	}
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestConcurrentQueue_Concurrent(t *testing.T) {
+	const producers, perProducer = 8, 1000
+	q := NewConcurrentQueue[int]()
+	var wg sync.WaitGroup
+	for i := 0; i < producers; i++ {
+		wg.Add(1)
+		go func(base int) {
+			defer wg.Done()
+			for j := 0; j < perProducer; j++ {
+				q.Enqueue(base*perProducer + j)
+			}
+		}(i)
+	}
+	var mutex sync.Mutex
+	seen := make(map[int]struct{}, producers*perProducer)
+	// 每个生产者的元素必须按照写入顺序出队
+	last := make(map[int]int, producers)
+	var cwg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		cwg.Add(1)
+		go func() {
+			defer cwg.Done()
+			for {
+				mutex.Lock()
+				done := len(seen) == producers*perProducer
+				mutex.Unlock()
+				if done {
+					return
+				}
+				val, err := q.Dequeue()
+				if err != nil {
+					continue
+				}
+				mutex.Lock()
+				seen[val] = struct{}{}
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	cwg.Wait()
+	assert.Len(t, seen, producers*perProducer)
+	assert.Equal(t, 0, q.Len())
+
+	// 单消费者时校验每个生产者内部的顺序
+	for i := 0; i < producers; i++ {
+		wg.Add(1)
+		go func(base int) {
+			defer wg.Done()
+			for j := 0; j < perProducer; j++ {
+				q.Enqueue(base*perProducer + j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	for q.Len() > 0 {
+		val, err := q.Dequeue()
+		require.NoError(t, err)
+		p := val / perProducer
+		if prev, ok := last[p]; ok {
+			assert.Less(t, prev, val)
+		}
+		last[p] = val
+	}
+}
+
+// goos: linux
+// goarch: amd64
+// pkg: github.com/wkRonin/toolkit/containerx/queue
+// cpu: Intel(R) Xeon(R) Processor
+// BenchmarkConcurrentQueue/ConcurrentQueue         	16631637	        80.24 ns/op
+// BenchmarkConcurrentQueue/channel                 	27337212	        51.74 ns/op
+// BenchmarkConcurrentQueue/RingBuffer              	24751956	        59.82 ns/op
+
+func BenchmarkConcurrentQueue(b *testing.B) {
+	b.Run("ConcurrentQueue", func(b *testing.B) {
+		q := NewConcurrentQueue[int]()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				q.Enqueue(1)
+				_, _ = q.Dequeue()
+			}
+		})
+	})
+	b.Run("channel", func(b *testing.B) {
+		ch := make(chan int, 1024)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				ch <- 1
+				<-ch
+			}
+		})
+	})
+	b.Run("RingBuffer", func(b *testing.B) {
+		r := NewRingBuffer[int](1024, OverwriteOldest)
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_ = r.Push(1)
+				_, _ = r.Pop()
+			}
+		})
+	})
+}
@@ -0,0 +1,77 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// cond 用 channel 实现的条件变量
+// sync.Cond 的 Wait 无法响应 context 的取消，所以这里自己实现一个
+type cond struct {
+	l  sync.Locker
+	ch chan struct{}
+}
+
+func newCond(l sync.Locker) *cond {
+	return &cond{
+		l:  l,
+		ch: make(chan struct{}),
+	}
+}
+
+// wait 必须在持有锁的情况下调用
+// 等待期间会释放锁，返回前无论成功与否都会重新加锁
+func (c *cond) wait(ctx context.Context) error {
+	ch := c.ch
+	c.l.Unlock()
+	select {
+	case <-ch:
+		c.l.Lock()
+		return nil
+	case <-ctx.Done():
+		c.l.Lock()
+		return ctx.Err()
+	}
+}
+
+// waitTimeout 和 wait 一样，但最多只等待 d，超时返回 nil
+func (c *cond) waitTimeout(ctx context.Context, d time.Duration) error {
+	ch := c.ch
+	c.l.Unlock()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ch:
+		c.l.Lock()
+		return nil
+	case <-timer.C:
+		c.l.Lock()
+		return nil
+	case <-ctx.Done():
+		c.l.Lock()
+		return ctx.Err()
+	}
+}
+
+// broadcast 必须在持有锁的情况下调用，唤醒所有等待者
+func (c *cond) broadcast() {
+	close(c.ch)
+	c.ch = make(chan struct{})
+}
@@ -0,0 +1,119 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Delayable 延时队列中的元素，Deadline 返回元素的到期时间
+type Delayable interface {
+	Deadline() time.Time
+}
+
+// DelayQueue 延时队列，并发安全
+// 使用 PriorityQueue 按到期时间组织成小顶堆，只有堆顶元素到期了才能出队
+type DelayQueue[T Delayable] struct {
+	pq    *PriorityQueue[T]
+	mutex *sync.Mutex
+	// 入队信号，有新元素入队时唤醒所有等待出队的 goroutine 重新检查堆顶
+	enqueueSignal *cond
+	// 出队信号，有界队列满时入队的 goroutine 在此等待
+	dequeueSignal *cond
+}
+
+// NewDelayQueue 创建延时队列 capacity <= 0 时，为无界队列，否则为有界队列
+func NewDelayQueue[T Delayable](capacity int, opts ...Option) *DelayQueue[T] {
+	mutex := &sync.Mutex{}
+	return &DelayQueue[T]{
+		pq: NewPriorityQueue[T](capacity, func(src T, dst T) bool {
+			return src.Deadline().Before(dst.Deadline())
+		}, opts...),
+		mutex:         mutex,
+		enqueueSignal: newCond(mutex),
+		dequeueSignal: newCond(mutex),
+	}
+}
+
+func (d *DelayQueue[T]) Len() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.pq.Len()
+}
+
+// Cap 无界队列返回0，有界队列返回创建队列时设置的值
+func (d *DelayQueue[T]) Cap() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.pq.Cap()
+}
+
+// Enqueue 入队，有界队列满的时候阻塞直到有空位或者 ctx 结束
+func (d *DelayQueue[T]) Enqueue(ctx context.Context, t T) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for d.pq.isFull() {
+		if err := d.dequeueSignal.wait(ctx); err != nil {
+			return err
+		}
+	}
+	err := d.pq.Enqueue(t)
+	if err != nil {
+		return err
+	}
+	// 新元素可能比堆顶更早到期，唤醒等待出队的 goroutine 重新计算等待时间
+	d.enqueueSignal.broadcast()
+	return nil
+}
+
+// Dequeue 出队，阻塞直到堆顶元素到期或者 ctx 结束
+// 等待期间如果有更早到期的元素入队，会提前醒来
+func (d *DelayQueue[T]) Dequeue(ctx context.Context) (T, error) {
+	var t T
+	if ctx.Err() != nil {
+		return t, ctx.Err()
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	for {
+		head, err := d.pq.Peek()
+		if err != nil {
+			// 队列为空，等待入队
+			if err = d.enqueueSignal.wait(ctx); err != nil {
+				return t, err
+			}
+			continue
+		}
+		delay := time.Until(head.Deadline())
+		if delay <= 0 {
+			t, err = d.pq.Dequeue()
+			if err != nil {
+				return t, err
+			}
+			d.dequeueSignal.broadcast()
+			return t, nil
+		}
+		if err = d.enqueueSignal.waitTimeout(ctx, delay); err != nil {
+			return t, err
+		}
+	}
+}
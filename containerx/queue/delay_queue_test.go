@@ -0,0 +1,129 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type delayElem struct {
+	val      int
+	deadline time.Time
+}
+
+func (d delayElem) Deadline() time.Time {
+	return d.deadline
+}
+
+func TestDelayQueue_Dequeue(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name    string
+		data    []delayElem
+		timeout time.Duration
+		wantErr error
+		wantVal int
+	}{
+		{
+			name:    "空队列超时",
+			timeout: 100 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "堆顶已到期",
+			data: []delayElem{
+				{val: 2, deadline: now.Add(time.Minute)},
+				{val: 1, deadline: now.Add(-time.Second)},
+			},
+			timeout: time.Second,
+			wantVal: 1,
+		},
+		{
+			name: "堆顶未到期，等待到期",
+			data: []delayElem{
+				{val: 2, deadline: now.Add(time.Minute)},
+				{val: 1, deadline: now.Add(200 * time.Millisecond)},
+			},
+			timeout: time.Second,
+			wantVal: 1,
+		},
+		{
+			name: "堆顶未到期，超时",
+			data: []delayElem{
+				{val: 1, deadline: now.Add(time.Minute)},
+			},
+			timeout: 100 * time.Millisecond,
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := NewDelayQueue[delayElem](0)
+			for _, el := range tc.data {
+				require.NoError(t, q.Enqueue(context.Background(), el))
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), tc.timeout)
+			defer cancel()
+			el, err := q.Dequeue(ctx)
+			assert.Equal(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.wantVal, el.val)
+			assert.False(t, time.Now().Before(el.deadline))
+		})
+	}
+}
+
+func TestDelayQueue_WakeUpEarlier(t *testing.T) {
+	q := NewDelayQueue[delayElem](0)
+	require.NoError(t, q.Enqueue(context.Background(), delayElem{val: 2, deadline: time.Now().Add(time.Minute)}))
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = q.Enqueue(context.Background(), delayElem{val: 1, deadline: time.Now().Add(50 * time.Millisecond)})
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	el, err := q.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, el.val)
+	assert.Equal(t, 1, q.Len())
+}
+
+func TestDelayQueue_Enqueue(t *testing.T) {
+	q := NewDelayQueue[delayElem](1)
+	require.NoError(t, q.Enqueue(context.Background(), delayElem{val: 1, deadline: time.Now()}))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := q.Enqueue(ctx, delayElem{val: 2, deadline: time.Now()})
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = q.Dequeue(context.Background())
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, q.Enqueue(ctx, delayElem{val: 3, deadline: time.Now()}))
+	assert.Equal(t, 1, q.Len())
+	assert.Equal(t, 1, q.Cap())
+}
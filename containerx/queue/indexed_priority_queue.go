@@ -0,0 +1,205 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import (
+	"errors"
+
+	"github.com/wkRonin/toolkit/containerx"
+	"github.com/wkRonin/toolkit/containerx/slice"
+)
+
+var ErrInvalidHandle = errors.New("indexedPriorityQueue handle is invalid or already removed")
+
+// Handle 入队时返回的句柄，用于 Update 和 Remove
+// 元素出队或者被删除之后，句柄失效
+type Handle[T any] struct {
+	value T
+	// 元素在堆中的下标，-1 表示已经不在队列中
+	index int
+	queue *IndexedPriorityQueue[T]
+}
+
+// Value 返回句柄对应元素当前的值
+func (h *Handle[T]) Value() T {
+	return h.value
+}
+
+// IndexedPriorityQueue 带索引的优先队列，支持通过句柄在 O(log n) 内修改优先级或者删除任意元素
+// 非并发安全
+type IndexedPriorityQueue[T any] struct {
+	compare        containerx.Comparator[T]
+	capacity       int
+	data           []*Handle[T]
+	shrinkStrategy slice.ShrinkStrategy
+}
+
+// NewIndexedPriorityQueue 创建带索引的优先队列 capacity <= 0 时，为无界队列，否则为有界队列
+func NewIndexedPriorityQueue[T any](capacity int, compare containerx.Comparator[T], opts ...Option) *IndexedPriorityQueue[T] {
+	sliceCap := capacity
+	if capacity < 1 {
+		capacity = 0
+		sliceCap = 64
+	}
+	o := newOptions(opts)
+	return &IndexedPriorityQueue[T]{
+		compare:        compare,
+		capacity:       capacity,
+		data:           make([]*Handle[T], 0, sliceCap),
+		shrinkStrategy: o.shrinkStrategy,
+	}
+}
+
+func (p *IndexedPriorityQueue[T]) Len() int {
+	return len(p.data)
+}
+
+// Cap 无界队列返回0，有界队列返回创建队列时设置的值
+func (p *IndexedPriorityQueue[T]) Cap() int {
+	return p.capacity
+}
+
+func (p *IndexedPriorityQueue[T]) IsBoundless() bool {
+	return p.capacity <= 0
+}
+
+func (p *IndexedPriorityQueue[T]) isFull() bool {
+	return p.capacity > 0 && len(p.data) == p.capacity
+}
+
+func (p *IndexedPriorityQueue[T]) shrinkIfNecessary() {
+	if p.IsBoundless() {
+		p.data = slice.ShrinkWith[*Handle[T]](p.data, p.shrinkStrategy)
+	}
+}
+
+// Enqueue 入队，返回的句柄可以用于 Update 和 Remove
+func (p *IndexedPriorityQueue[T]) Enqueue(t T) (*Handle[T], error) {
+	if p.isFull() {
+		return nil, ErrOutOfCapacity
+	}
+	h := &Handle[T]{
+		value: t,
+		index: len(p.data),
+		queue: p,
+	}
+	p.data = append(p.data, h)
+	p.up(h.index)
+	return h, nil
+}
+
+func (p *IndexedPriorityQueue[T]) Peek() (T, error) {
+	if len(p.data) == 0 {
+		var t T
+		return t, ErrEmptyQueue
+	}
+	return p.data[0].value, nil
+}
+
+func (p *IndexedPriorityQueue[T]) Dequeue() (T, error) {
+	if len(p.data) == 0 {
+		var t T
+		return t, ErrEmptyQueue
+	}
+	return p.removeAt(0), nil
+}
+
+// Update 修改句柄对应元素的值，并重新调整它在堆中的位置
+func (p *IndexedPriorityQueue[T]) Update(h *Handle[T], t T) error {
+	if !p.valid(h) {
+		return ErrInvalidHandle
+	}
+	h.value = t
+	p.fix(h.index)
+	return nil
+}
+
+// Remove 删除句柄对应的元素
+func (p *IndexedPriorityQueue[T]) Remove(h *Handle[T]) (T, error) {
+	if !p.valid(h) {
+		var t T
+		return t, ErrInvalidHandle
+	}
+	return p.removeAt(h.index), nil
+}
+
+func (p *IndexedPriorityQueue[T]) valid(h *Handle[T]) bool {
+	return h != nil && h.queue == p && h.index >= 0 &&
+		h.index < len(p.data) && p.data[h.index] == h
+}
+
+func (p *IndexedPriorityQueue[T]) removeAt(i int) T {
+	last := len(p.data) - 1
+	h := p.data[i]
+	if i != last {
+		p.swap(i, last)
+	}
+	p.data[last] = nil
+	p.data = p.data[:last]
+	if i != last {
+		p.fix(i)
+	}
+	h.index = -1
+	p.shrinkIfNecessary()
+	return h.value
+}
+
+// fix 下标 i 的元素值变化之后，重新调整堆
+func (p *IndexedPriorityQueue[T]) fix(i int) {
+	if !p.down(i) {
+		p.up(i)
+	}
+}
+
+// up 自下向上堆化
+func (p *IndexedPriorityQueue[T]) up(i int) {
+	for i > 0 {
+		father := (i - 1) / 2
+		if !p.compare(p.data[i].value, p.data[father].value) {
+			break
+		}
+		p.swap(i, father)
+		i = father
+	}
+}
+
+// down 自上向下堆化，返回元素是否发生了移动
+func (p *IndexedPriorityQueue[T]) down(i int) bool {
+	start, n := i, len(p.data)
+	for {
+		left := 2*i + 1
+		if left >= n {
+			break
+		}
+		tmp := left
+		if right := left + 1; right < n && p.compare(p.data[right].value, p.data[left].value) {
+			tmp = right
+		}
+		if !p.compare(p.data[tmp].value, p.data[i].value) {
+			break
+		}
+		p.swap(i, tmp)
+		i = tmp
+	}
+	return i > start
+}
+
+func (p *IndexedPriorityQueue[T]) swap(i, j int) {
+	p.data[i], p.data[j] = p.data[j], p.data[i]
+	p.data[i].index = i
+	p.data[j].index = j
+}
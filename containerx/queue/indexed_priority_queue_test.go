@@ -0,0 +1,199 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexedPriorityQueue_Enqueue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		capacity int
+		data     []int
+		element  int
+		wantErr  error
+	}{
+		{
+			name:     "有界满队列",
+			capacity: 6,
+			data:     []int{6, 5, 4, 3, 2, 1},
+			element:  10,
+			wantErr:  ErrOutOfCapacity,
+		},
+		{
+			name:     "有界非空不满队列",
+			capacity: 12,
+			data:     []int{6, 5, 4, 3, 2, 1},
+			element:  10,
+		},
+		{
+			name:     "无界非空队列",
+			capacity: 0,
+			data:     []int{6, 5, 4, 3, 2, 1},
+			element:  10,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, _ := indexedPriorityQueueOf(t, tc.capacity, tc.data)
+			h, err := q.Enqueue(tc.element)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.capacity, q.Cap())
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.element, h.Value())
+			assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 10}, drain(t, q))
+		})
+	}
+}
+
+func TestIndexedPriorityQueue_Update(t *testing.T) {
+	testCases := []struct {
+		name   string
+		data   []int
+		target int
+		newVal int
+		want   []int
+	}{
+		{
+			name:   "提高优先级",
+			data:   []int{6, 5, 4, 3, 2, 1},
+			target: 5,
+			newVal: 0,
+			want:   []int{0, 1, 2, 3, 4, 6},
+		},
+		{
+			name:   "降低优先级",
+			data:   []int{6, 5, 4, 3, 2, 1},
+			target: 1,
+			newVal: 10,
+			want:   []int{2, 3, 4, 5, 6, 10},
+		},
+		{
+			name:   "优先级不变",
+			data:   []int{6, 5, 4, 3, 2, 1},
+			target: 3,
+			newVal: 3,
+			want:   []int{1, 2, 3, 4, 5, 6},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, handles := indexedPriorityQueueOf(t, 0, tc.data)
+			require.NoError(t, q.Update(handles[tc.target], tc.newVal))
+			assert.Equal(t, tc.newVal, handles[tc.target].Value())
+			assert.Equal(t, tc.want, drain(t, q))
+		})
+	}
+}
+
+func TestIndexedPriorityQueue_Remove(t *testing.T) {
+	testCases := []struct {
+		name   string
+		data   []int
+		target int
+		want   []int
+	}{
+		{
+			name:   "删除堆顶",
+			data:   []int{6, 5, 4, 3, 2, 1},
+			target: 1,
+			want:   []int{2, 3, 4, 5, 6},
+		},
+		{
+			name:   "删除中间元素",
+			data:   []int{6, 5, 4, 3, 2, 1},
+			target: 4,
+			want:   []int{1, 2, 3, 5, 6},
+		},
+		{
+			name:   "删除最后一个元素",
+			data:   []int{6, 5, 4, 3, 2, 1},
+			target: 6,
+			want:   []int{1, 2, 3, 4, 5},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, handles := indexedPriorityQueueOf(t, 0, tc.data)
+			val, err := q.Remove(handles[tc.target])
+			require.NoError(t, err)
+			assert.Equal(t, tc.target, val)
+			// 重复删除
+			_, err = q.Remove(handles[tc.target])
+			assert.Equal(t, ErrInvalidHandle, err)
+			assert.Equal(t, ErrInvalidHandle, q.Update(handles[tc.target], 0))
+			assert.Equal(t, tc.want, drain(t, q))
+		})
+	}
+}
+
+func TestIndexedPriorityQueue_InvalidHandle(t *testing.T) {
+	q, handles := indexedPriorityQueueOf(t, 0, []int{3, 2, 1})
+	other, _ := indexedPriorityQueueOf(t, 0, []int{3, 2, 1})
+	assert.Equal(t, ErrInvalidHandle, other.Update(handles[1], 0))
+	_, err := other.Remove(handles[1])
+	assert.Equal(t, ErrInvalidHandle, err)
+	assert.Equal(t, ErrInvalidHandle, q.Update(nil, 0))
+
+	// 出队之后句柄失效
+	val, err := q.Dequeue()
+	require.NoError(t, err)
+	assert.Equal(t, 1, val)
+	assert.Equal(t, ErrInvalidHandle, q.Update(handles[1], 0))
+	_, err = q.Peek()
+	assert.NoError(t, err)
+}
+
+func TestIndexedPriorityQueue_Shrink(t *testing.T) {
+	q := NewIndexedPriorityQueue[int](0, compare())
+	for i := 0; i < 2000; i++ {
+		_, err := q.Enqueue(i)
+		require.NoError(t, err)
+	}
+	for i := 0; i < 1990; i++ {
+		_, err := q.Dequeue()
+		require.NoError(t, err)
+	}
+	assert.Less(t, cap(q.data), 2000)
+}
+
+func indexedPriorityQueueOf(t *testing.T, capacity int, data []int) (*IndexedPriorityQueue[int], map[int]*Handle[int]) {
+	q := NewIndexedPriorityQueue[int](capacity, compare())
+	handles := make(map[int]*Handle[int], len(data))
+	for _, el := range data {
+		h, err := q.Enqueue(el)
+		require.NoError(t, err)
+		handles[el] = h
+	}
+	return q, handles
+}
+
+func drain(t *testing.T, q *IndexedPriorityQueue[int]) []int {
+	res := make([]int, 0, q.Len())
+	for q.Len() > 0 {
+		el, err := q.Dequeue()
+		require.NoError(t, err)
+		res = append(res, el)
+	}
+	return res
+}
@@ -0,0 +1,43 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import "github.com/wkRonin/toolkit/containerx/slice"
+
+// Option 创建队列时的可选配置，对本包中所有的队列都生效
+type Option func(o *options)
+
+type options struct {
+	shrinkStrategy slice.ShrinkStrategy
+}
+
+// WithShrinkStrategy 设置无界队列出队时使用的缩容策略，默认为 slice.DefaultShrinkStrategy
+func WithShrinkStrategy(strategy slice.ShrinkStrategy) Option {
+	return func(o *options) {
+		o.shrinkStrategy = strategy
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		shrinkStrategy: slice.DefaultShrinkStrategy{},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
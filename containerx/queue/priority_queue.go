@@ -29,24 +29,27 @@ var (
 )
 
 type PriorityQueue[T any] struct {
-	compare  containerx.Comparator[T]
-	capacity int
-	data     []T
-	length   int
+	compare        containerx.Comparator[T]
+	capacity       int
+	data           []T
+	length         int
+	shrinkStrategy slice.ShrinkStrategy
 }
 
 // NewPriorityQueue 创建优先队列 capacity <= 0 时，为无界队列，否则为有界队列
-func NewPriorityQueue[T any](capacity int, compare containerx.Comparator[T]) *PriorityQueue[T] {
+func NewPriorityQueue[T any](capacity int, compare containerx.Comparator[T], opts ...Option) *PriorityQueue[T] {
 	sliceCap := capacity
 	if capacity < 1 {
 		capacity = 0
 		sliceCap = 64
 	}
+	o := newOptions(opts)
 	return &PriorityQueue[T]{
-		compare:  compare,
-		capacity: capacity,
-		data:     make([]T, 0, sliceCap),
-		length:   0,
+		compare:        compare,
+		capacity:       capacity,
+		data:           make([]T, 0, sliceCap),
+		length:         0,
+		shrinkStrategy: o.shrinkStrategy,
 	}
 }
 
@@ -73,7 +76,7 @@ func (p *PriorityQueue[T]) IsBoundless() bool {
 
 func (p *PriorityQueue[T]) shrinkIfNecessary() {
 	if p.IsBoundless() {
-		p.data = slice.Shrink[T](p.data)
+		p.data = slice.ShrinkWith[T](p.data, p.shrinkStrategy)
 	}
 }
 
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/wkRonin/toolkit/containerx"
+	"github.com/wkRonin/toolkit/containerx/slice"
 )
 
 func TestNewPriorityQueue(t *testing.T) {
@@ -468,6 +469,39 @@ func TestPriorityQueue_Shrink(t *testing.T) {
 	}
 }
 
+func TestPriorityQueue_ShrinkStrategy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		strategy slice.ShrinkStrategy
+		sliceCap int
+	}{
+		{
+			name:     "永不缩容",
+			strategy: slice.NeverShrinkStrategy{},
+			sliceCap: 2560,
+		},
+		{
+			// 逐步缩容，最后一次缩容时长度为39
+			name:     "缩容到长度加余量",
+			strategy: slice.HeadroomShrinkStrategy{Headroom: 1, MinCapacity: 64},
+			sliceCap: 78,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q := NewPriorityQueue[int](0, compare(), WithShrinkStrategy(tc.strategy))
+			for i := 0; i < 2000; i++ {
+				require.NoError(t, q.Enqueue(i))
+			}
+			for i := 0; i < 1990; i++ {
+				_, err := q.Dequeue()
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.sliceCap, cap(q.data))
+		})
+	}
+}
+
 func priorityQueueOf(capacity int, data []int, compare containerx.Comparator[int]) *PriorityQueue[int] {
 	q := NewPriorityQueue[int](capacity, compare)
 	for _, el := range data {
@@ -0,0 +1,106 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import "sync"
+
+// RingBufferMode 环形缓冲区满了之后的写入策略
+type RingBufferMode int
+
+const (
+	// RejectWhenFull 满了之后拒绝写入，返回 ErrOutOfCapacity
+	RejectWhenFull RingBufferMode = iota
+	// OverwriteOldest 满了之后覆盖最早写入的元素
+	OverwriteOldest
+)
+
+// RingBuffer 固定容量的环形缓冲区，先进先出，并发安全
+// 和 channel 相比，可以方便地实现满了之后丢弃最早的元素
+type RingBuffer[T any] struct {
+	mutex sync.Mutex
+	mode  RingBufferMode
+	data  []T
+	// head 指向最早写入的元素
+	head   int
+	length int
+}
+
+// NewRingBuffer 创建环形缓冲区，capacity 必须大于0
+func NewRingBuffer[T any](capacity int, mode RingBufferMode) *RingBuffer[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &RingBuffer[T]{
+		mode: mode,
+		data: make([]T, capacity),
+	}
+}
+
+func (r *RingBuffer[T]) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.length
+}
+
+func (r *RingBuffer[T]) Cap() int {
+	return len(r.data)
+}
+
+// Push 写入元素，OverwriteOldest 模式下永远不会返回 error
+func (r *RingBuffer[T]) Push(t T) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.length == len(r.data) {
+		if r.mode == RejectWhenFull {
+			return ErrOutOfCapacity
+		}
+		// 覆盖最早的元素，head 向后移动
+		r.data[r.head] = t
+		r.head = (r.head + 1) % len(r.data)
+		return nil
+	}
+	r.data[(r.head+r.length)%len(r.data)] = t
+	r.length++
+	return nil
+}
+
+// Pop 取出最早写入的元素，为空时返回 ErrEmptyQueue
+func (r *RingBuffer[T]) Pop() (T, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var zero T
+	if r.length == 0 {
+		return zero, ErrEmptyQueue
+	}
+	t := r.data[r.head]
+	// 置零避免继续引用已经取出的元素
+	r.data[r.head] = zero
+	r.head = (r.head + 1) % len(r.data)
+	r.length--
+	return t, nil
+}
+
+// Peek 返回最早写入的元素但是不取出，为空时返回 ErrEmptyQueue
+func (r *RingBuffer[T]) Peek() (T, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.length == 0 {
+		var t T
+		return t, ErrEmptyQueue
+	}
+	return r.data[r.head], nil
+}
@@ -0,0 +1,99 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package queue
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBuffer_Push(t *testing.T) {
+	testCases := []struct {
+		name     string
+		mode     RingBufferMode
+		capacity int
+		data     []int
+		wantErrs []error
+		want     []int
+	}{
+		{
+			name:     "未满",
+			mode:     RejectWhenFull,
+			capacity: 3,
+			data:     []int{1, 2},
+			wantErrs: []error{nil, nil},
+			want:     []int{1, 2},
+		},
+		{
+			name:     "满了拒绝写入",
+			mode:     RejectWhenFull,
+			capacity: 3,
+			data:     []int{1, 2, 3, 4, 5},
+			wantErrs: []error{nil, nil, nil, ErrOutOfCapacity, ErrOutOfCapacity},
+			want:     []int{1, 2, 3},
+		},
+		{
+			name:     "满了覆盖最早的元素",
+			mode:     OverwriteOldest,
+			capacity: 3,
+			data:     []int{1, 2, 3, 4, 5},
+			wantErrs: []error{nil, nil, nil, nil, nil},
+			want:     []int{3, 4, 5},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRingBuffer[int](tc.capacity, tc.mode)
+			errs := make([]error, 0, len(tc.data))
+			for _, d := range tc.data {
+				errs = append(errs, r.Push(d))
+			}
+			assert.Equal(t, tc.wantErrs, errs)
+			assert.Equal(t, len(tc.want), r.Len())
+			assert.Equal(t, tc.capacity, r.Cap())
+			res := make([]int, 0, r.Len())
+			for r.Len() > 0 {
+				peek, err := r.Peek()
+				require.NoError(t, err)
+				val, err := r.Pop()
+				require.NoError(t, err)
+				assert.Equal(t, peek, val)
+				res = append(res, val)
+			}
+			assert.Equal(t, tc.want, res)
+			_, err := r.Pop()
+			assert.Equal(t, ErrEmptyQueue, err)
+		})
+	}
+}
+
+func TestRingBuffer_Wrap(t *testing.T) {
+	r := NewRingBuffer[int](3, RejectWhenFull)
+	// 交替读写，让下标绕过数组末尾
+	for i := 0; i < 10; i++ {
+		require.NoError(t, r.Push(i))
+		require.NoError(t, r.Push(i+100))
+		val, err := r.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, i, val)
+		val, err = r.Pop()
+		require.NoError(t, err)
+		assert.Equal(t, i+100, val)
+	}
+}
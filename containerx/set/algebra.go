@@ -0,0 +1,76 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package set
+
+// 以下是各个 Set 实现共用的集合运算，结果写入 dst 中
+// dst 由调用方创建，这样结果的类型和调用方保持一致
+
+func union[T comparable](dst, a, b Set[T]) Set[T] {
+	add := func(key T) bool {
+		dst.Add(key)
+		return true
+	}
+	a.Range(add)
+	b.Range(add)
+	return dst
+}
+
+func intersect[T comparable](dst, a, b Set[T]) Set[T] {
+	// 遍历较小的集合
+	if a.Len() > b.Len() {
+		a, b = b, a
+	}
+	a.Range(func(key T) bool {
+		if b.Exist(key) {
+			dst.Add(key)
+		}
+		return true
+	})
+	return dst
+}
+
+func difference[T comparable](dst, a, b Set[T]) Set[T] {
+	a.Range(func(key T) bool {
+		if !b.Exist(key) {
+			dst.Add(key)
+		}
+		return true
+	})
+	return dst
+}
+
+func symmetricDifference[T comparable](dst, a, b Set[T]) Set[T] {
+	difference[T](dst, a, b)
+	difference[T](dst, b, a)
+	return dst
+}
+
+func isSubset[T comparable](a, b Set[T]) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+	res := true
+	a.Range(func(key T) bool {
+		res = b.Exist(key)
+		return res
+	})
+	return res
+}
+
+func isEqual[T comparable](a, b Set[T]) bool {
+	return a.Len() == b.Len() && isSubset[T](a, b)
+}
@@ -0,0 +1,141 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package set
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet_Algebra(t *testing.T) {
+	builders := map[string]func(vals ...int) Set[int]{
+		"MapSet": func(vals ...int) Set[int] {
+			s := NewMapSet[int](len(vals))
+			for _, v := range vals {
+				s.Add(v)
+			}
+			return s
+		},
+		"ConcurrentSet": func(vals ...int) Set[int] {
+			s := NewConcurrentSet[int](len(vals))
+			for _, v := range vals {
+				s.Add(v)
+			}
+			return s
+		},
+		"TreeSet": func(vals ...int) Set[int] {
+			s := NewTreeSet[int](less)
+			for _, v := range vals {
+				s.Add(v)
+			}
+			return s
+		},
+	}
+	testCases := []struct {
+		name       string
+		a          []int
+		b          []int
+		union      []int
+		intersect  []int
+		difference []int
+		symmetric  []int
+		isSubset   bool
+		equal      bool
+	}{
+		{
+			name:       "部分重叠",
+			a:          []int{1, 2, 3},
+			b:          []int{2, 3, 4},
+			union:      []int{1, 2, 3, 4},
+			intersect:  []int{2, 3},
+			difference: []int{1},
+			symmetric:  []int{1, 4},
+		},
+		{
+			name:       "子集",
+			a:          []int{1, 2},
+			b:          []int{1, 2, 3},
+			union:      []int{1, 2, 3},
+			intersect:  []int{1, 2},
+			difference: []int{},
+			symmetric:  []int{3},
+			isSubset:   true,
+		},
+		{
+			name:       "相等",
+			a:          []int{1, 2, 3},
+			b:          []int{3, 2, 1},
+			union:      []int{1, 2, 3},
+			intersect:  []int{1, 2, 3},
+			difference: []int{},
+			symmetric:  []int{},
+			isSubset:   true,
+			equal:      true,
+		},
+		{
+			name:       "空集",
+			a:          []int{},
+			b:          []int{1},
+			union:      []int{1},
+			intersect:  []int{},
+			difference: []int{},
+			symmetric:  []int{1},
+			isSubset:   true,
+		},
+	}
+	for name, build := range builders {
+		for _, tc := range testCases {
+			t.Run(name+"/"+tc.name, func(t *testing.T) {
+				a, b := build(tc.a...), build(tc.b...)
+				assert.Equal(t, tc.union, sortedKeys(a.Union(b)))
+				assert.Equal(t, tc.intersect, sortedKeys(a.Intersect(b)))
+				assert.Equal(t, tc.difference, sortedKeys(a.Difference(b)))
+				assert.Equal(t, tc.symmetric, sortedKeys(a.SymmetricDifference(b)))
+				assert.Equal(t, tc.isSubset, a.IsSubset(b))
+				assert.Equal(t, tc.equal, a.Equal(b))
+				// 运算不会修改原集合
+				assert.Equal(t, len(tc.a), a.Len())
+				assert.Equal(t, len(tc.b), b.Len())
+			})
+		}
+	}
+}
+
+func TestSet_Range(t *testing.T) {
+	s := NewMapSet[int](10)
+	for i := 0; i < 10; i++ {
+		s.Add(i)
+	}
+	cnt := 0
+	s.Range(func(key int) bool {
+		cnt++
+		return cnt < 5
+	})
+	assert.Equal(t, 5, cnt)
+}
+
+func sortedKeys(s Set[int]) []int {
+	keys := s.Keys()
+	sort.Ints(keys)
+	return keys
+}
+
+func less(a, b int) bool {
+	return a < b
+}
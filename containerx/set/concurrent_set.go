@@ -0,0 +1,96 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package set
+
+import "sync"
+
+// ConcurrentSet 并发安全的集合，使用读写锁保护 MapSet
+type ConcurrentSet[T comparable] struct {
+	mutex sync.RWMutex
+	s     *MapSet[T]
+}
+
+func NewConcurrentSet[T comparable](size int) *ConcurrentSet[T] {
+	return &ConcurrentSet[T]{
+		s: NewMapSet[T](size),
+	}
+}
+
+func (c *ConcurrentSet[T]) Add(key T) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.s.Add(key)
+}
+
+func (c *ConcurrentSet[T]) Delete(key T) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.s.Delete(key)
+}
+
+func (c *ConcurrentSet[T]) Exist(key T) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.s.Exist(key)
+}
+
+// Keys 方法返回的元素顺序不固定
+func (c *ConcurrentSet[T]) Keys() []T {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.s.Keys()
+}
+
+func (c *ConcurrentSet[T]) Len() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.s.Len()
+}
+
+// Range 遍历的是调用时的快照，遍历过程中不持有锁
+// 所以 fn 中可以安全地修改当前集合，但修改不会反映到本次遍历中
+func (c *ConcurrentSet[T]) Range(fn func(key T) bool) {
+	for _, key := range c.Keys() {
+		if !fn(key) {
+			return
+		}
+	}
+}
+
+func (c *ConcurrentSet[T]) Union(other Set[T]) Set[T] {
+	return union[T](NewConcurrentSet[T](0), c, other)
+}
+
+func (c *ConcurrentSet[T]) Intersect(other Set[T]) Set[T] {
+	return intersect[T](NewConcurrentSet[T](0), c, other)
+}
+
+func (c *ConcurrentSet[T]) Difference(other Set[T]) Set[T] {
+	return difference[T](NewConcurrentSet[T](0), c, other)
+}
+
+func (c *ConcurrentSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return symmetricDifference[T](NewConcurrentSet[T](0), c, other)
+}
+
+func (c *ConcurrentSet[T]) IsSubset(other Set[T]) bool {
+	return isSubset[T](c, other)
+}
+
+func (c *ConcurrentSet[T]) Equal(other Set[T]) bool {
+	return isEqual[T](c, other)
+}
@@ -0,0 +1,51 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package set
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrentSet(t *testing.T) {
+	s := NewConcurrentSet[int](10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(base int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s.Add(base*100 + j)
+				_ = s.Exist(j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 1000, s.Len())
+
+	// Range 中修改集合不会死锁
+	s.Range(func(key int) bool {
+		if key%2 == 0 {
+			s.Delete(key)
+		}
+		return true
+	})
+	assert.Equal(t, 500, s.Len())
+	assert.True(t, s.Equal(s.Union(s)))
+}
@@ -6,6 +6,21 @@ type Set[T comparable] interface {
 	// Exist 返回是否存在这个元素
 	Exist(key T) bool
 	Keys() []T
+	Len() int
+	// Range 遍历集合中的元素，fn 返回 false 时停止遍历
+	Range(fn func(key T) bool)
+	// Union 并集
+	Union(other Set[T]) Set[T]
+	// Intersect 交集
+	Intersect(other Set[T]) Set[T]
+	// Difference 差集，在当前集合中但不在 other 中的元素
+	Difference(other Set[T]) Set[T]
+	// SymmetricDifference 对称差集，只在其中一个集合中出现的元素
+	SymmetricDifference(other Set[T]) Set[T]
+	// IsSubset 当前集合是否是 other 的子集
+	IsSubset(other Set[T]) bool
+	// Equal 两个集合的元素是否完全相同
+	Equal(other Set[T]) bool
 }
 
 type MapSet[T comparable] struct {
@@ -38,3 +53,40 @@ func (s *MapSet[T]) Keys() []T {
 	}
 	return ans
 }
+
+func (s *MapSet[T]) Len() int {
+	return len(s.m)
+}
+
+// Range 遍历顺序不固定
+func (s *MapSet[T]) Range(fn func(key T) bool) {
+	for key := range s.m {
+		if !fn(key) {
+			return
+		}
+	}
+}
+
+func (s *MapSet[T]) Union(other Set[T]) Set[T] {
+	return union[T](NewMapSet[T](s.Len()+other.Len()), s, other)
+}
+
+func (s *MapSet[T]) Intersect(other Set[T]) Set[T] {
+	return intersect[T](NewMapSet[T](0), s, other)
+}
+
+func (s *MapSet[T]) Difference(other Set[T]) Set[T] {
+	return difference[T](NewMapSet[T](0), s, other)
+}
+
+func (s *MapSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return symmetricDifference[T](NewMapSet[T](0), s, other)
+}
+
+func (s *MapSet[T]) IsSubset(other Set[T]) bool {
+	return isSubset[T](s, other)
+}
+
+func (s *MapSet[T]) Equal(other Set[T]) bool {
+	return isEqual[T](s, other)
+}
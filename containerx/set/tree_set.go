@@ -0,0 +1,122 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package set
+
+import (
+	"github.com/wkRonin/toolkit/containerx"
+	"github.com/wkRonin/toolkit/containerx/slice"
+)
+
+// TreeSet 有序集合，按照 compare 定义的顺序组织元素
+// compare(a, b) 和 compare(b, a) 都为 false 时认为 a 和 b 是同一个元素
+// 底层是有序切片，Exist 是 O(log n)，Add 和 Delete 需要移动元素，是 O(n)
+// 适合读多写少、元素不多的场景，频繁增删的大集合请使用 tree.TreeMap
+// 非并发安全
+type TreeSet[T comparable] struct {
+	compare containerx.Comparator[T]
+	// 有序切片，通过二分查找定位元素
+	data []T
+}
+
+func NewTreeSet[T comparable](compare containerx.Comparator[T]) *TreeSet[T] {
+	return &TreeSet[T]{
+		compare: compare,
+	}
+}
+
+// search 返回第一个不小于 key 的下标，以及该下标上的元素是否等于 key
+func (s *TreeSet[T]) search(key T) (int, bool) {
+	low, high := 0, len(s.data)
+	for low < high {
+		mid := int(uint(low+high) >> 1)
+		if s.compare(s.data[mid], key) {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low, low < len(s.data) && !s.compare(key, s.data[low])
+}
+
+func (s *TreeSet[T]) Add(key T) {
+	idx, ok := s.search(key)
+	if ok {
+		return
+	}
+	var zero T
+	s.data = append(s.data, zero)
+	copy(s.data[idx+1:], s.data[idx:])
+	s.data[idx] = key
+}
+
+func (s *TreeSet[T]) Delete(key T) {
+	idx, ok := s.search(key)
+	if !ok {
+		return
+	}
+	// slice.Delete 会把空出来的位置置零，并在需要的时候缩容，避免继续引用被删除的元素
+	s.data, _, _ = slice.Delete(s.data, idx)
+}
+
+func (s *TreeSet[T]) Exist(key T) bool {
+	_, ok := s.search(key)
+	return ok
+}
+
+// Keys 按照 compare 定义的顺序返回元素
+func (s *TreeSet[T]) Keys() []T {
+	ans := make([]T, len(s.data))
+	copy(ans, s.data)
+	return ans
+}
+
+func (s *TreeSet[T]) Len() int {
+	return len(s.data)
+}
+
+// Range 按照 compare 定义的顺序遍历
+func (s *TreeSet[T]) Range(fn func(key T) bool) {
+	for _, key := range s.data {
+		if !fn(key) {
+			return
+		}
+	}
+}
+
+func (s *TreeSet[T]) Union(other Set[T]) Set[T] {
+	return union[T](NewTreeSet[T](s.compare), s, other)
+}
+
+func (s *TreeSet[T]) Intersect(other Set[T]) Set[T] {
+	return intersect[T](NewTreeSet[T](s.compare), s, other)
+}
+
+func (s *TreeSet[T]) Difference(other Set[T]) Set[T] {
+	return difference[T](NewTreeSet[T](s.compare), s, other)
+}
+
+func (s *TreeSet[T]) SymmetricDifference(other Set[T]) Set[T] {
+	return symmetricDifference[T](NewTreeSet[T](s.compare), s, other)
+}
+
+func (s *TreeSet[T]) IsSubset(other Set[T]) bool {
+	return isSubset[T](s, other)
+}
+
+func (s *TreeSet[T]) Equal(other Set[T]) bool {
+	return isEqual[T](s, other)
+}
@@ -0,0 +1,92 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package set
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeSet(t *testing.T) {
+	testCases := []struct {
+		name     string
+		add      []int
+		del      []int
+		wantKeys []int
+	}{
+		{
+			name:     "有序",
+			add:      []int{5, 3, 9, 1, 7},
+			wantKeys: []int{1, 3, 5, 7, 9},
+		},
+		{
+			name:     "重复添加",
+			add:      []int{3, 1, 3, 2, 1},
+			wantKeys: []int{1, 2, 3},
+		},
+		{
+			name:     "删除",
+			add:      []int{5, 3, 9, 1, 7},
+			del:      []int{1, 7, 100},
+			wantKeys: []int{3, 5, 9},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewTreeSet[int](less)
+			for _, v := range tc.add {
+				s.Add(v)
+			}
+			for _, v := range tc.del {
+				s.Delete(v)
+				assert.False(t, s.Exist(v))
+			}
+			assert.Equal(t, tc.wantKeys, s.Keys())
+			assert.Equal(t, len(tc.wantKeys), s.Len())
+			res := make([]int, 0, s.Len())
+			s.Range(func(key int) bool {
+				res = append(res, key)
+				return true
+			})
+			assert.Equal(t, tc.wantKeys, res)
+		})
+	}
+}
+
+func TestTreeSet_UnionOrdered(t *testing.T) {
+	a, b := NewTreeSet[int](less), NewMapSet[int](3)
+	for _, v := range []int{5, 1, 3} {
+		a.Add(v)
+		b.Add(v + 1)
+	}
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, a.Union(b).Keys())
+}
+
+func TestTreeSet_DeleteReleasesElement(t *testing.T) {
+	s := NewTreeSet[*int](func(src, dst *int) bool {
+		return *src < *dst
+	})
+	one, two, three := 1, 2, 3
+	s.Add(&one)
+	s.Add(&two)
+	s.Add(&three)
+	s.Delete(&one)
+	assert.Equal(t, []*int{&two, &three}, s.Keys())
+	// 底层数组中空出来的位置被置零，不再引用被删除的元素
+	assert.Nil(t, s.data[:cap(s.data)][len(s.data)])
+}
@@ -0,0 +1,229 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package skiplist
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/wkRonin/toolkit/containerx"
+)
+
+const (
+	// maxLevel 和 Redis ZSET 保持一致
+	maxLevel = 32
+	// probability 每一层晋升到上一层的概率
+	probability = 0.25
+)
+
+// SkipList 跳表，按照 compare 定义的顺序组织元素，语义和 Redis ZSET 保持一致
+// compare(a, b) 和 compare(b, a) 都为 false 时认为 a 和 b 是同一个元素
+// 和 ZSET 一样，排名从0开始
+// 非并发安全
+type SkipList[T any] struct {
+	compare containerx.Comparator[T]
+	header  *skipListNode[T]
+	level   int
+	length  int
+	r       *rand.Rand
+}
+
+type skipListNode[T any] struct {
+	val    T
+	levels []skipListLevel[T]
+}
+
+type skipListLevel[T any] struct {
+	next *skipListNode[T]
+	// span 当前节点到 next 之间跨越的节点数量，用于计算排名
+	span int
+}
+
+func NewSkipList[T any](compare containerx.Comparator[T]) *SkipList[T] {
+	return &SkipList[T]{
+		compare: compare,
+		header:  &skipListNode[T]{levels: make([]skipListLevel[T], maxLevel)},
+		level:   1,
+		r:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (s *SkipList[T]) Len() int {
+	return s.length
+}
+
+func (s *SkipList[T]) randomLevel() int {
+	level := 1
+	for level < maxLevel && s.r.Float64() < probability {
+		level++
+	}
+	return level
+}
+
+// Insert 插入元素，返回是否新增了元素
+// 已经存在相同的元素时会用 val 覆盖它，返回 false
+// 注意：如果要修改元素的排序依据（例如 ZSET 中的 score），需要先 Delete 再 Insert
+func (s *SkipList[T]) Insert(val T) bool {
+	var update [maxLevel]*skipListNode[T]
+	var rank [maxLevel]int
+	x := s.header
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].next != nil && s.compare(x.levels[i].next.val, val) {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+	if n := x.levels[0].next; n != nil && !s.compare(val, n.val) {
+		n.val = val
+		return false
+	}
+
+	level := s.randomLevel()
+	if level > s.level {
+		for i := s.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = s.header
+			update[i].levels[i].span = s.length
+		}
+		s.level = level
+	}
+	x = &skipListNode[T]{val: val, levels: make([]skipListLevel[T], level)}
+	for i := 0; i < level; i++ {
+		x.levels[i].next = update[i].levels[i].next
+		update[i].levels[i].next = x
+		x.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+	// 更高的层没有指向新节点，但是跨越了新节点
+	for i := level; i < s.level; i++ {
+		update[i].levels[i].span++
+	}
+	s.length++
+	return true
+}
+
+// Delete 删除元素，返回元素是否存在
+func (s *SkipList[T]) Delete(val T) bool {
+	var update [maxLevel]*skipListNode[T]
+	x := s.header
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && s.compare(x.levels[i].next.val, val) {
+			x = x.levels[i].next
+		}
+		update[i] = x
+	}
+	x = x.levels[0].next
+	if x == nil || s.compare(val, x.val) {
+		return false
+	}
+	for i := 0; i < s.level; i++ {
+		if update[i].levels[i].next == x {
+			update[i].levels[i].span += x.levels[i].span - 1
+			update[i].levels[i].next = x.levels[i].next
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+	for s.level > 1 && s.header.levels[s.level-1].next == nil {
+		s.level--
+	}
+	s.length--
+	return true
+}
+
+// Search 查找和 val 相同的元素
+func (s *SkipList[T]) Search(val T) (T, bool) {
+	x := s.lowerBound(val)
+	if x == nil || s.compare(val, x.val) {
+		var t T
+		return t, false
+	}
+	return x.val, true
+}
+
+// Rank 返回元素的排名，从0开始，元素不存在时返回 -1, false
+func (s *SkipList[T]) Rank(val T) (int, bool) {
+	rank := 0
+	x := s.header
+	for i := s.level - 1; i >= 0; i-- {
+		// 前进到最后一个小于等于 val 的节点
+		for x.levels[i].next != nil && !s.compare(val, x.levels[i].next.val) {
+			rank += x.levels[i].span
+			x = x.levels[i].next
+		}
+		if x != s.header && !s.compare(x.val, val) {
+			return rank - 1, true
+		}
+	}
+	return -1, false
+}
+
+// ElementAt 返回排名为 rank 的元素，rank 从0开始
+func (s *SkipList[T]) ElementAt(rank int) (T, bool) {
+	if rank < 0 || rank >= s.length {
+		var t T
+		return t, false
+	}
+	target := rank + 1
+	traversed := 0
+	x := s.header
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && traversed+x.levels[i].span <= target {
+			traversed += x.levels[i].span
+			x = x.levels[i].next
+		}
+		if traversed == target {
+			return x.val, true
+		}
+	}
+	var t T
+	return t, false
+}
+
+// Range 按顺序遍历 [min, max] 区间内的元素，和 ZRANGEBYSCORE 一样是闭区间
+// fn 返回 false 时停止遍历
+func (s *SkipList[T]) Range(min, max T, fn func(val T) bool) {
+	for x := s.lowerBound(min); x != nil && !s.compare(max, x.val); x = x.levels[0].next {
+		if !fn(x.val) {
+			return
+		}
+	}
+}
+
+// ForEach 按顺序遍历所有元素，fn 返回 false 时停止遍历
+func (s *SkipList[T]) ForEach(fn func(val T) bool) {
+	for x := s.header.levels[0].next; x != nil; x = x.levels[0].next {
+		if !fn(x.val) {
+			return
+		}
+	}
+}
+
+// lowerBound 返回第一个大于等于 val 的节点
+func (s *SkipList[T]) lowerBound(val T) *skipListNode[T] {
+	x := s.header
+	for i := s.level - 1; i >= 0; i-- {
+		for x.levels[i].next != nil && s.compare(x.levels[i].next.val, val) {
+			x = x.levels[i].next
+		}
+	}
+	return x.levels[0].next
+}
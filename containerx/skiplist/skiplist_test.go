@@ -0,0 +1,182 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package skiplist
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type member struct {
+	name  string
+	score int
+}
+
+// 和 ZSET 一样，先按 score 排序，score 相同时按 name 排序
+func compareMember(a, b member) bool {
+	if a.score != b.score {
+		return a.score < b.score
+	}
+	return a.name < b.name
+}
+
+func TestSkipList_InsertDelete(t *testing.T) {
+	testCases := []struct {
+		name       string
+		insert     []member
+		del        []member
+		wantResult []bool
+		want       []member
+	}{
+		{
+			name:   "有序",
+			insert: []member{{"c", 3}, {"a", 1}, {"b", 2}},
+			want:   []member{{"a", 1}, {"b", 2}, {"c", 3}},
+		},
+		{
+			name:   "score 相同按 name 排序",
+			insert: []member{{"b", 1}, {"a", 1}, {"c", 0}},
+			want:   []member{{"c", 0}, {"a", 1}, {"b", 1}},
+		},
+		{
+			name:       "删除",
+			insert:     []member{{"c", 3}, {"a", 1}, {"b", 2}},
+			del:        []member{{"b", 2}, {"d", 4}, {"a", 1}},
+			wantResult: []bool{true, false, true},
+			want:       []member{{"c", 3}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewSkipList[member](compareMember)
+			for _, m := range tc.insert {
+				assert.True(t, s.Insert(m))
+			}
+			res := make([]bool, 0, len(tc.del))
+			for _, m := range tc.del {
+				res = append(res, s.Delete(m))
+			}
+			if len(tc.del) > 0 {
+				assert.Equal(t, tc.wantResult, res)
+			}
+			assert.Equal(t, tc.want, toSlice(s))
+			assert.Equal(t, len(tc.want), s.Len())
+		})
+	}
+}
+
+func TestSkipList_RankAndElementAt(t *testing.T) {
+	s := NewSkipList[member](compareMember)
+	for i, name := range []string{"e", "d", "c", "b", "a"} {
+		s.Insert(member{name: name, score: i * 10})
+	}
+	for i, m := range toSlice(s) {
+		rank, ok := s.Rank(m)
+		assert.True(t, ok)
+		assert.Equal(t, i, rank)
+		el, ok := s.ElementAt(i)
+		assert.True(t, ok)
+		assert.Equal(t, m, el)
+	}
+	rank, ok := s.Rank(member{name: "x", score: 15})
+	assert.False(t, ok)
+	assert.Equal(t, -1, rank)
+	_, ok = s.ElementAt(5)
+	assert.False(t, ok)
+	_, ok = s.ElementAt(-1)
+	assert.False(t, ok)
+
+	m, ok := s.Search(member{name: "c", score: 20})
+	assert.True(t, ok)
+	assert.Equal(t, "c", m.name)
+	_, ok = s.Search(member{name: "c", score: 21})
+	assert.False(t, ok)
+}
+
+func TestSkipList_Range(t *testing.T) {
+	s := NewSkipList[member](compareMember)
+	for i := 0; i < 10; i++ {
+		s.Insert(member{name: "m", score: i})
+	}
+	testCases := []struct {
+		name  string
+		min   int
+		max   int
+		limit int
+		want  []int
+	}{
+		{name: "闭区间", min: 3, max: 5, want: []int{3, 4, 5}},
+		{name: "超出范围", min: -10, max: 1, want: []int{0, 1}},
+		{name: "空区间", min: 20, max: 30, want: []int{}},
+		{name: "提前结束", min: 0, max: 9, limit: 2, want: []int{0, 1}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := make([]int, 0, len(tc.want))
+			// name 取最小值和最大值，保证包含边界上的所有成员
+			s.Range(member{name: "", score: tc.min}, member{name: "~", score: tc.max}, func(val member) bool {
+				res = append(res, val.score)
+				return tc.limit == 0 || len(res) < tc.limit
+			})
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestSkipList_Random(t *testing.T) {
+	s := NewSkipList[int](func(a, b int) bool { return a < b })
+	ref := make(map[int]struct{})
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		v := r.Intn(500)
+		_, exist := ref[v]
+		if r.Intn(3) == 0 {
+			assert.Equal(t, exist, s.Delete(v))
+			delete(ref, v)
+		} else {
+			assert.Equal(t, !exist, s.Insert(v))
+			ref[v] = struct{}{}
+		}
+	}
+	want := make([]int, 0, len(ref))
+	for v := range ref {
+		want = append(want, v)
+	}
+	sort.Ints(want)
+	require.Equal(t, len(want), s.Len())
+	for i, v := range want {
+		rank, ok := s.Rank(v)
+		require.True(t, ok)
+		require.Equal(t, i, rank)
+		el, ok := s.ElementAt(i)
+		require.True(t, ok)
+		require.Equal(t, v, el)
+	}
+}
+
+func toSlice(s *SkipList[member]) []member {
+	res := make([]member, 0, s.Len())
+	s.ForEach(func(val member) bool {
+		res = append(res, val)
+		return true
+	})
+	return res
+}
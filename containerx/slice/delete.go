@@ -0,0 +1,65 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package slice
+
+import "errors"
+
+var ErrIndexOutOfRange = errors.New("slice index out of range")
+
+// Delete 删除 index 上的元素，返回删除后的切片和被删除的元素
+// 删除后会调用 Shrink 判断是否需要缩容，注意 src 的底层数组会被修改
+func Delete[T any](src []T, index int) ([]T, T, error) {
+	length := len(src)
+	if index < 0 || index >= length {
+		var zero T
+		return src, zero, ErrIndexOutOfRange
+	}
+	res := src[index]
+	copy(src[index:], src[index+1:])
+	// 置零避免底层数组继续引用被删除的元素
+	var zero T
+	src[length-1] = zero
+	return Shrink[T](src[:length-1]), res, nil
+}
+
+// DeleteFunc 原地删除所有 fn 为 true 的元素，删除后会调用 Shrink 判断是否需要缩容
+func DeleteFunc[T any](src []T, fn func(t T) bool) []T {
+	pos := 0
+	for _, t := range src {
+		if !fn(t) {
+			src[pos] = t
+			pos++
+		}
+	}
+	var zero T
+	for i := pos; i < len(src); i++ {
+		src[i] = zero
+	}
+	return Shrink[T](src[:pos])
+}
+
+// Insert 在 index 上插入元素，index 等于 len(src) 时追加到末尾
+func Insert[T any](src []T, index int, t T) ([]T, error) {
+	if index < 0 || index > len(src) {
+		return src, ErrIndexOutOfRange
+	}
+	var zero T
+	src = append(src, zero)
+	copy(src[index+1:], src[index:])
+	src[index] = t
+	return src, nil
+}
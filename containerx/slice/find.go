@@ -0,0 +1,43 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package slice
+
+// Find 返回第一个 fn 为 true 的元素
+func Find[T any](src []T, fn func(t T) bool) (T, bool) {
+	for _, t := range src {
+		if fn(t) {
+			return t, true
+		}
+	}
+	var t T
+	return t, false
+}
+
+// Index 返回 dst 第一次出现的下标，不存在时返回 -1
+func Index[T comparable](src []T, dst T) int {
+	for i, t := range src {
+		if t == dst {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains 判断 src 中是否存在 dst
+func Contains[T comparable](src []T, dst T) bool {
+	return Index[T](src, dst) >= 0
+}
@@ -0,0 +1,41 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package slice
+
+// ChunkBy 把 fn 返回值相同的相邻元素切分到同一组
+// 例如按天切分已经按时间排序的数据
+func ChunkBy[T any, K comparable](src []T, fn func(t T) K) [][]T {
+	res := make([][]T, 0)
+	start := 0
+	for i := 1; i <= len(src); i++ {
+		if i == len(src) || fn(src[i]) != fn(src[i-1]) {
+			res = append(res, src[start:i:i])
+			start = i
+		}
+	}
+	return res
+}
+
+// GroupBy 按照 fn 的返回值分组，组内元素保持原来的顺序
+func GroupBy[T any, K comparable](src []T, fn func(t T) K) map[K][]T {
+	res := make(map[K][]T)
+	for _, t := range src {
+		key := fn(t)
+		res[key] = append(res[key], t)
+	}
+	return res
+}
@@ -0,0 +1,46 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package slice
+
+// Map 把 src 中的每个元素转换成另一个类型
+func Map[Src any, Dst any](src []Src, fn func(src Src) Dst) []Dst {
+	dst := make([]Dst, len(src))
+	for i, s := range src {
+		dst[i] = fn(s)
+	}
+	return dst
+}
+
+// Filter 返回 fn 为 true 的元素组成的新切片，不会修改 src
+func Filter[T any](src []T, fn func(t T) bool) []T {
+	res := make([]T, 0, len(src))
+	for _, t := range src {
+		if fn(t) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// Reduce 从 initial 开始依次把每个元素累积到结果上
+func Reduce[T any, R any](src []T, initial R, fn func(acc R, t T) R) R {
+	acc := initial
+	for _, t := range src {
+		acc = fn(acc, t)
+	}
+	return acc
+}
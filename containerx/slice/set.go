@@ -0,0 +1,82 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package slice
+
+// 以下集合运算的结果都会去重，元素顺序和第一次出现的顺序一致
+
+// Union 并集
+func Union[T comparable](src []T, dst []T) []T {
+	seen := make(map[T]struct{}, len(src)+len(dst))
+	res := make([]T, 0, len(src)+len(dst))
+	for _, list := range [][]T{src, dst} {
+		for _, t := range list {
+			if _, ok := seen[t]; !ok {
+				seen[t] = struct{}{}
+				res = append(res, t)
+			}
+		}
+	}
+	return res
+}
+
+// Intersect 交集
+func Intersect[T comparable](src []T, dst []T) []T {
+	dstSet := toSet[T](dst)
+	res := make([]T, 0, len(src))
+	for _, t := range src {
+		if _, ok := dstSet[t]; ok {
+			res = append(res, t)
+			// 删除之后保证结果去重
+			delete(dstSet, t)
+		}
+	}
+	return res
+}
+
+// Diff 差集，在 src 中但是不在 dst 中的元素
+func Diff[T comparable](src []T, dst []T) []T {
+	dstSet := toSet[T](dst)
+	res := make([]T, 0, len(src))
+	for _, t := range src {
+		if _, ok := dstSet[t]; !ok {
+			res = append(res, t)
+			dstSet[t] = struct{}{}
+		}
+	}
+	return res
+}
+
+// Dedup 去重
+func Dedup[T comparable](src []T) []T {
+	seen := make(map[T]struct{}, len(src))
+	res := make([]T, 0, len(src))
+	for _, t := range src {
+		if _, ok := seen[t]; !ok {
+			seen[t] = struct{}{}
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+func toSet[T comparable](src []T) map[T]struct{} {
+	m := make(map[T]struct{}, len(src))
+	for _, t := range src {
+		m[t] = struct{}{}
+	}
+	return m
+}
@@ -16,10 +16,59 @@
 
 package slice
 
-// Shrink 切片缩容
+// ShrinkStrategy 缩容策略
+type ShrinkStrategy interface {
+	// Capacity 根据切片当前的容量 c 和长度 l 计算缩容后的容量，第二个返回值表示是否需要缩容
+	Capacity(c, l int) (int, bool)
+}
+
+// DefaultShrinkStrategy 默认的缩容策略
+// 容量不超过64时不缩容
+// 容量超过2048并且长度不足一半时，缩容为原来的0.625倍
+// 容量不超过2048并且长度不足1/4时，缩容为原来的一半
+type DefaultShrinkStrategy struct{}
+
+func (DefaultShrinkStrategy) Capacity(c, l int) (int, bool) {
+	return calCapacity(c, l)
+}
+
+// NeverShrinkStrategy 永不缩容，适合对延迟敏感、长度在阈值附近来回波动的场景
+type NeverShrinkStrategy struct{}
+
+func (NeverShrinkStrategy) Capacity(c, l int) (int, bool) {
+	return c, false
+}
+
+// HeadroomShrinkStrategy 缩容到长度加上一定的余量，即 l * (1 + Headroom)，且不小于 MinCapacity
+// 只有容量超过目标容量的两倍时才缩容，避免长度在阈值附近波动时反复扩缩容
+type HeadroomShrinkStrategy struct {
+	Headroom    float64
+	MinCapacity int
+}
+
+func (h HeadroomShrinkStrategy) Capacity(c, l int) (int, bool) {
+	target := int(float64(l) * (1 + h.Headroom))
+	if target < h.MinCapacity {
+		target = h.MinCapacity
+	}
+	if target < l {
+		target = l
+	}
+	if c <= 2*target {
+		return c, false
+	}
+	return target, true
+}
+
+// Shrink 切片缩容，使用 DefaultShrinkStrategy
 func Shrink[T any](src []T) []T {
+	return ShrinkWith[T](src, DefaultShrinkStrategy{})
+}
+
+// ShrinkWith 使用指定的缩容策略缩容
+func ShrinkWith[T any](src []T, strategy ShrinkStrategy) []T {
 	c, l := cap(src), len(src)
-	n, changed := calCapacity(c, l)
+	n, changed := strategy.Capacity(c, l)
 	if !changed {
 		return src
 	}
@@ -28,15 +77,16 @@ func Shrink[T any](src []T) []T {
 	return s
 }
 
+// calCapacity 用乘法判断比例，避免 l 为0时除零
 func calCapacity(c, l int) (int, bool) {
 	if c <= 64 {
 		return c, false
 	}
-	if c > 2048 && (c/l >= 2) {
+	if c > 2048 && (c >= 2*l) {
 		factor := 0.625
 		return int(float32(c) * float32(factor)), true
 	}
-	if c <= 2048 && (c/l >= 4) {
+	if c <= 2048 && (c >= 4*l) {
 		return c / 2, true
 	}
 	return c, false
@@ -0,0 +1,54 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package slice
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShrinkStrategy(t *testing.T) {
+	testCases := []struct {
+		name        string
+		strategy    ShrinkStrategy
+		c           int
+		l           int
+		wantCap     int
+		wantChanged bool
+	}{
+		{name: "默认，小于64", strategy: DefaultShrinkStrategy{}, c: 64, l: 1, wantCap: 64},
+		{name: "默认，小于2048, 不足1/4", strategy: DefaultShrinkStrategy{}, c: 1000, l: 200, wantCap: 500, wantChanged: true},
+		{name: "默认，小于2048, 超过1/4", strategy: DefaultShrinkStrategy{}, c: 1000, l: 300, wantCap: 1000},
+		{name: "默认，大于2048，不足一半", strategy: DefaultShrinkStrategy{}, c: 3000, l: 1000, wantCap: 1875, wantChanged: true},
+		{name: "默认，长度为0", strategy: DefaultShrinkStrategy{}, c: 1000, l: 0, wantCap: 500, wantChanged: true},
+		{name: "永不缩容", strategy: NeverShrinkStrategy{}, c: 3000, l: 0, wantCap: 3000},
+		{name: "余量，未超过两倍", strategy: HeadroomShrinkStrategy{Headroom: 0.5}, c: 300, l: 100, wantCap: 300},
+		{name: "余量，超过两倍", strategy: HeadroomShrinkStrategy{Headroom: 0.5}, c: 301, l: 100, wantCap: 150, wantChanged: true},
+		{name: "余量，最小容量", strategy: HeadroomShrinkStrategy{Headroom: 0.5, MinCapacity: 64}, c: 1000, l: 0, wantCap: 64, wantChanged: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, changed := tc.strategy.Capacity(tc.c, tc.l)
+			assert.Equal(t, tc.wantCap, c)
+			assert.Equal(t, tc.wantChanged, changed)
+			res := ShrinkWith[int](make([]int, tc.l, tc.c), tc.strategy)
+			assert.Equal(t, tc.wantCap, cap(res))
+			assert.Equal(t, tc.l, len(res))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package slice
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapFilterReduce(t *testing.T) {
+	src := []int{1, 2, 3, 4, 5}
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, Map[int, string](src, strconv.Itoa))
+	assert.Equal(t, []int{2, 4}, Filter[int](src, func(t int) bool { return t%2 == 0 }))
+	assert.Equal(t, 15, Reduce[int, int](src, 0, func(acc int, t int) int { return acc + t }))
+	assert.Equal(t, []string{}, Map[int, string](nil, strconv.Itoa))
+}
+
+func TestFind(t *testing.T) {
+	src := []int{1, 2, 3, 2}
+	val, ok := Find[int](src, func(t int) bool { return t > 1 })
+	assert.True(t, ok)
+	assert.Equal(t, 2, val)
+	_, ok = Find[int](src, func(t int) bool { return t > 10 })
+	assert.False(t, ok)
+	assert.Equal(t, 1, Index[int](src, 2))
+	assert.Equal(t, -1, Index[int](src, 10))
+	assert.True(t, Contains[int](src, 3))
+	assert.False(t, Contains[int](src, 10))
+}
+
+func TestDelete(t *testing.T) {
+	testCases := []struct {
+		name      string
+		src       []int
+		index     int
+		wantSlice []int
+		wantVal   int
+		wantErr   error
+	}{
+		{
+			name:      "删除第一个",
+			src:       []int{1, 2, 3},
+			index:     0,
+			wantSlice: []int{2, 3},
+			wantVal:   1,
+		},
+		{
+			name:      "删除最后一个",
+			src:       []int{1, 2, 3},
+			index:     2,
+			wantSlice: []int{1, 2},
+			wantVal:   3,
+		},
+		{
+			name:      "下标越界",
+			src:       []int{1, 2, 3},
+			index:     3,
+			wantSlice: []int{1, 2, 3},
+			wantErr:   ErrIndexOutOfRange,
+		},
+		{
+			name:      "负数下标",
+			src:       []int{1, 2, 3},
+			index:     -1,
+			wantSlice: []int{1, 2, 3},
+			wantErr:   ErrIndexOutOfRange,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, val, err := Delete[int](tc.src, tc.index)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantSlice, res)
+			assert.Equal(t, tc.wantVal, val)
+		})
+	}
+}
+
+func TestDelete_Shrink(t *testing.T) {
+	src := make([]int, 2000)
+	for i := 0; i < 1990; i++ {
+		src, _, _ = Delete[int](src, 0)
+	}
+	assert.Equal(t, 10, len(src))
+	assert.Less(t, cap(src), 2000)
+
+	src = make([]int, 2000)
+	for i := range src {
+		src[i] = i
+	}
+	src = DeleteFunc[int](src, func(t int) bool { return t >= 10 })
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, src)
+	assert.Equal(t, 1000, cap(src))
+}
+
+func TestInsert(t *testing.T) {
+	testCases := []struct {
+		name      string
+		src       []int
+		index     int
+		wantSlice []int
+		wantErr   error
+	}{
+		{name: "头部", src: []int{1, 2}, index: 0, wantSlice: []int{100, 1, 2}},
+		{name: "中间", src: []int{1, 2}, index: 1, wantSlice: []int{1, 100, 2}},
+		{name: "尾部", src: []int{1, 2}, index: 2, wantSlice: []int{1, 2, 100}},
+		{name: "越界", src: []int{1, 2}, index: 3, wantSlice: []int{1, 2}, wantErr: ErrIndexOutOfRange},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := Insert[int](tc.src, tc.index, 100)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantSlice, res)
+		})
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	src, dst := []int{1, 2, 2, 3}, []int{3, 4, 4, 2}
+	assert.Equal(t, []int{1, 2, 3, 4}, Union[int](src, dst))
+	assert.Equal(t, []int{2, 3}, Intersect[int](src, dst))
+	assert.Equal(t, []int{1}, Diff[int](src, dst))
+	assert.Equal(t, []int{3, 4, 2}, Dedup[int](dst))
+}
+
+func TestGroup(t *testing.T) {
+	src := []int{1, 3, 2, 4, 5, 6, 8}
+	isOdd := func(t int) bool { return t%2 == 1 }
+	assert.Equal(t, [][]int{{1, 3}, {2, 4}, {5}, {6, 8}}, ChunkBy[int, bool](src, isOdd))
+	assert.Equal(t, [][]int{}, ChunkBy[int, bool](nil, isOdd))
+	assert.Equal(t, map[bool][]int{
+		true:  {1, 3, 5},
+		false: {2, 4, 6, 8},
+	}, GroupBy[int, bool](src, isOdd))
+}
@@ -0,0 +1,120 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package timingwheel
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	statePending int32 = iota
+	stateFired
+	stateStopped
+)
+
+// Timer AfterFunc 返回的定时任务句柄
+type Timer struct {
+	// 到期时间，单位纳秒
+	expiration int64
+	fn         func()
+	state      atomic.Int32
+	// 当前所在的 bucket，任务在不同层级之间迁移时会变化
+	b       atomic.Pointer[bucket]
+	element *list.Element
+}
+
+// Stop 取消定时任务，和 time.Timer 一样，返回 false 说明任务已经执行或者已经被取消
+func (t *Timer) Stop() bool {
+	stopped := t.state.CompareAndSwap(statePending, stateStopped)
+	// 任务可能正在被迁移到另一个 bucket，所以需要循环删除
+	// 迁移之后没有删掉也没关系，到期时会因为状态不对而被丢弃
+	for b := t.b.Load(); b != nil; b = t.b.Load() {
+		if b.remove(t) {
+			break
+		}
+	}
+	return stopped
+}
+
+// bucket 时间轮上的一个格子，里面的任务到期时间处于同一个 tick 内
+type bucket struct {
+	// 格子的到期时间，单位纳秒，-1 表示格子为空
+	expiration atomic.Int64
+	mutex      sync.Mutex
+	timers     *list.List
+}
+
+func newBucket() *bucket {
+	b := &bucket{
+		timers: list.New(),
+	}
+	b.expiration.Store(-1)
+	return b
+}
+
+// Deadline 实现 queue.Delayable 接口
+func (b *bucket) Deadline() time.Time {
+	return time.Unix(0, b.expiration.Load())
+}
+
+// setExpiration 返回到期时间是否发生了变化，变化了说明需要重新加入延时队列
+func (b *bucket) setExpiration(expiration int64) bool {
+	return b.expiration.Swap(expiration) != expiration
+}
+
+func (b *bucket) add(t *Timer) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	t.element = b.timers.PushBack(t)
+	t.b.Store(b)
+}
+
+func (b *bucket) remove(t *Timer) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if t.b.Load() != b {
+		return false
+	}
+	b.timers.Remove(t.element)
+	t.b.Store(nil)
+	t.element = nil
+	return true
+}
+
+// flush 取出所有任务交给 reinsert，要么执行，要么放到更低层的时间轮
+func (b *bucket) flush(reinsert func(t *Timer)) {
+	b.mutex.Lock()
+	timers := make([]*Timer, 0, b.timers.Len())
+	for e := b.timers.Front(); e != nil; {
+		next := e.Next()
+		t := e.Value.(*Timer)
+		b.timers.Remove(e)
+		t.b.Store(nil)
+		t.element = nil
+		timers = append(timers, t)
+		e = next
+	}
+	b.setExpiration(-1)
+	b.mutex.Unlock()
+
+	for _, t := range timers {
+		reinsert(t)
+	}
+}
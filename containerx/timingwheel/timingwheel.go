@@ -0,0 +1,179 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package timingwheel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wkRonin/toolkit/containerx/queue"
+)
+
+// TimingWheel 分层时间轮，适合管理大量的定时任务，例如锁续约、会话过期
+// 所有层级的格子都放在同一个延时队列中，由一个 goroutine 驱动
+// 只有格子到期时才会被唤醒，没有任务时不会空转
+type TimingWheel struct {
+	// 添加任务时持有读锁，推进时间时持有写锁
+	mutex  sync.RWMutex
+	wheel  *wheel
+	queue  *queue.DelayQueue[*bucket]
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTimingWheel 创建时间轮，tick 为最底层每一格的时间跨度，也是定时的精度
+// wheelSize 为每一层的格子数量，超过 tick * wheelSize 的任务放到上一层时间轮中
+func NewTimingWheel(tick time.Duration, wheelSize int) *TimingWheel {
+	if tick <= 0 {
+		tick = time.Millisecond
+	}
+	if wheelSize < 1 {
+		wheelSize = 1
+	}
+	q := queue.NewDelayQueue[*bucket](0)
+	return &TimingWheel{
+		wheel: newWheel(int64(tick), int64(wheelSize), time.Now().UnixNano(), q),
+		queue: q,
+	}
+}
+
+// Start 启动驱动时间轮的 goroutine
+func (tw *TimingWheel) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	tw.cancel = cancel
+	tw.wg.Add(1)
+	go func() {
+		defer tw.wg.Done()
+		for {
+			b, err := tw.queue.Dequeue(ctx)
+			if err != nil {
+				return
+			}
+			tw.mutex.Lock()
+			tw.wheel.advanceClock(b.expiration.Load())
+			b.flush(tw.addOrRun)
+			tw.mutex.Unlock()
+		}
+	}()
+}
+
+// Stop 停止时间轮，还没有到期的任务不会再执行
+func (tw *TimingWheel) Stop() {
+	if tw.cancel != nil {
+		tw.cancel()
+	}
+	tw.wg.Wait()
+}
+
+// AfterFunc 在 d 之后执行 fn，fn 在新的 goroutine 中执行
+// 精度为 tick，同一个 tick 内到期的任务会一起执行，所以可能提前不超过一个 tick
+func (tw *TimingWheel) AfterFunc(d time.Duration, fn func()) *Timer {
+	t := &Timer{
+		expiration: time.Now().Add(d).UnixNano(),
+		fn:         fn,
+	}
+	tw.mutex.RLock()
+	tw.addOrRun(t)
+	tw.mutex.RUnlock()
+	return t
+}
+
+func (tw *TimingWheel) addOrRun(t *Timer) {
+	if t.state.Load() != statePending {
+		return
+	}
+	if tw.wheel.add(t) {
+		return
+	}
+	// 已经到期
+	if t.state.CompareAndSwap(statePending, stateFired) {
+		go t.fn()
+	}
+}
+
+// wheel 时间轮中的一层
+type wheel struct {
+	// tick 和 interval 的单位都是纳秒
+	tick      int64
+	wheelSize int64
+	interval  int64
+	// currentTime 总是 tick 的整数倍
+	currentTime atomic.Int64
+	buckets     []*bucket
+	queue       *queue.DelayQueue[*bucket]
+	// 上一层时间轮，按需创建
+	overflow atomic.Pointer[wheel]
+}
+
+func newWheel(tick, wheelSize, startTime int64, q *queue.DelayQueue[*bucket]) *wheel {
+	buckets := make([]*bucket, wheelSize)
+	for i := range buckets {
+		buckets[i] = newBucket()
+	}
+	w := &wheel{
+		tick:      tick,
+		wheelSize: wheelSize,
+		interval:  tick * wheelSize,
+		buckets:   buckets,
+		queue:     q,
+	}
+	w.currentTime.Store(truncate(startTime, tick))
+	return w
+}
+
+// add 返回 false 说明任务已经到期
+func (w *wheel) add(t *Timer) bool {
+	current := w.currentTime.Load()
+	if t.expiration < current+w.tick {
+		return false
+	}
+	if t.expiration < current+w.interval {
+		virtualID := t.expiration / w.tick
+		b := w.buckets[virtualID%w.wheelSize]
+		b.add(t)
+		// 格子被复用时才需要重新加入延时队列，否则已经在队列中了
+		if b.setExpiration(virtualID * w.tick) {
+			// 无界队列，不会阻塞
+			_ = w.queue.Enqueue(context.Background(), b)
+		}
+		return true
+	}
+	overflow := w.overflow.Load()
+	if overflow == nil {
+		w.overflow.CompareAndSwap(nil, newWheel(w.interval, w.wheelSize, current, w.queue))
+		overflow = w.overflow.Load()
+	}
+	return overflow.add(t)
+}
+
+func (w *wheel) advanceClock(expiration int64) {
+	current := w.currentTime.Load()
+	if expiration < current+w.tick {
+		return
+	}
+	current = truncate(expiration, w.tick)
+	w.currentTime.Store(current)
+	if overflow := w.overflow.Load(); overflow != nil {
+		overflow.advanceClock(current)
+	}
+}
+
+func truncate(x, m int64) int64 {
+	return x - x%m
+}
@@ -0,0 +1,113 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package timingwheel
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimingWheel_AfterFunc(t *testing.T) {
+	// 每层 8 格，tick 为 1ms，第一层覆盖 8ms，第二层 64ms，第三层 512ms
+	tw := NewTimingWheel(time.Millisecond, 8)
+	tw.Start()
+	defer tw.Stop()
+
+	testCases := []struct {
+		name  string
+		delay time.Duration
+	}{
+		{name: "已经到期", delay: -time.Second},
+		{name: "第一层", delay: 5 * time.Millisecond},
+		{name: "第二层", delay: 50 * time.Millisecond},
+		{name: "第三层", delay: 300 * time.Millisecond},
+		{name: "更高层", delay: 600 * time.Millisecond},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Now()
+			ch := make(chan time.Time, 1)
+			tw.AfterFunc(tc.delay, func() {
+				ch <- time.Now()
+			})
+			select {
+			case fired := <-ch:
+				elapsed := fired.Sub(start)
+				delay := tc.delay
+				if delay < 0 {
+					delay = 0
+				}
+				// 精度为一个 tick
+				assert.GreaterOrEqual(t, elapsed, delay-time.Millisecond)
+				assert.Less(t, elapsed, delay+100*time.Millisecond)
+			case <-time.After(2 * time.Second):
+				t.Fatal("定时任务没有执行")
+			}
+		})
+	}
+}
+
+func TestTimingWheel_Stop(t *testing.T) {
+	tw := NewTimingWheel(time.Millisecond, 8)
+	tw.Start()
+	defer tw.Stop()
+
+	var fired atomic.Bool
+	timer := tw.AfterFunc(50*time.Millisecond, func() {
+		fired.Store(true)
+	})
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop())
+	time.Sleep(100 * time.Millisecond)
+	assert.False(t, fired.Load())
+
+	ch := make(chan struct{})
+	timer = tw.AfterFunc(time.Millisecond, func() {
+		close(ch)
+	})
+	<-ch
+	// 已经执行的任务无法取消
+	assert.False(t, timer.Stop())
+}
+
+func TestTimingWheel_Many(t *testing.T) {
+	tw := NewTimingWheel(time.Millisecond, 20)
+	tw.Start()
+	defer tw.Stop()
+
+	const n = 10000
+	var wg sync.WaitGroup
+	var cnt atomic.Int64
+	wg.Add(n / 2)
+	for i := 0; i < n; i++ {
+		timer := tw.AfterFunc(time.Duration(i%200)*time.Millisecond, func() {
+			cnt.Add(1)
+			wg.Done()
+		})
+		// 取消一半
+		if i%2 == 1 {
+			timer.Stop()
+		}
+	}
+	wg.Wait()
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int64(n/2), cnt.Load())
+}
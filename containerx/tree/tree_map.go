@@ -0,0 +1,331 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package tree
+
+import (
+	"github.com/wkRonin/toolkit/containerx"
+)
+
+// TreeMap 有序 map，使用左倾红黑树实现，按照 compare 定义的顺序组织 key
+// compare(a, b) 和 compare(b, a) 都为 false 时认为 a 和 b 是同一个 key
+// 非并发安全
+type TreeMap[K any, V any] struct {
+	compare containerx.Comparator[K]
+	root    *node[K, V]
+	size    int
+}
+
+type node[K any, V any] struct {
+	key   K
+	val   V
+	left  *node[K, V]
+	right *node[K, V]
+	red   bool
+}
+
+func NewTreeMap[K any, V any](compare containerx.Comparator[K]) *TreeMap[K, V] {
+	return &TreeMap[K, V]{
+		compare: compare,
+	}
+}
+
+func (t *TreeMap[K, V]) Len() int {
+	return t.size
+}
+
+// Put 写入键值对，key 已经存在时覆盖原来的值
+func (t *TreeMap[K, V]) Put(key K, val V) {
+	t.root = t.put(t.root, key, val)
+	t.root.red = false
+}
+
+func (t *TreeMap[K, V]) Get(key K) (V, bool) {
+	n := t.find(key)
+	if n == nil {
+		var v V
+		return v, false
+	}
+	return n.val, true
+}
+
+// Delete 删除 key，返回被删除的值，key 不存在时第二个返回值为 false
+func (t *TreeMap[K, V]) Delete(key K) (V, bool) {
+	n := t.find(key)
+	if n == nil {
+		var v V
+		return v, false
+	}
+	val := n.val
+	if !isRed(t.root.left) && !isRed(t.root.right) {
+		t.root.red = true
+	}
+	t.root = t.delete(t.root, key)
+	if t.root != nil {
+		t.root.red = false
+	}
+	t.size--
+	return val, true
+}
+
+// Min 返回最小的键值对，map 为空时第三个返回值为 false
+func (t *TreeMap[K, V]) Min() (K, V, bool) {
+	if t.root == nil {
+		return t.none()
+	}
+	n := minNode(t.root)
+	return n.key, n.val, true
+}
+
+// Max 返回最大的键值对，map 为空时第三个返回值为 false
+func (t *TreeMap[K, V]) Max() (K, V, bool) {
+	if t.root == nil {
+		return t.none()
+	}
+	n := t.root
+	for n.right != nil {
+		n = n.right
+	}
+	return n.key, n.val, true
+}
+
+// Floor 返回小于等于 key 的最大键值对
+func (t *TreeMap[K, V]) Floor(key K) (K, V, bool) {
+	var res *node[K, V]
+	n := t.root
+	for n != nil {
+		if t.compare(key, n.key) {
+			n = n.left
+		} else if t.compare(n.key, key) {
+			res = n
+			n = n.right
+		} else {
+			return n.key, n.val, true
+		}
+	}
+	if res == nil {
+		return t.none()
+	}
+	return res.key, res.val, true
+}
+
+// Ceiling 返回大于等于 key 的最小键值对
+func (t *TreeMap[K, V]) Ceiling(key K) (K, V, bool) {
+	var res *node[K, V]
+	n := t.root
+	for n != nil {
+		if t.compare(n.key, key) {
+			n = n.right
+		} else if t.compare(key, n.key) {
+			res = n
+			n = n.left
+		} else {
+			return n.key, n.val, true
+		}
+	}
+	if res == nil {
+		return t.none()
+	}
+	return res.key, res.val, true
+}
+
+// Range 按顺序遍历 [from, to) 区间内的键值对，fn 返回 false 时停止遍历
+func (t *TreeMap[K, V]) Range(from, to K, fn func(key K, val V) bool) {
+	t.rangeNode(t.root, from, to, fn)
+}
+
+// ForEach 按顺序遍历所有键值对，fn 返回 false 时停止遍历
+func (t *TreeMap[K, V]) ForEach(fn func(key K, val V) bool) {
+	t.forEach(t.root, fn)
+}
+
+// Keys 按顺序返回所有的 key
+func (t *TreeMap[K, V]) Keys() []K {
+	keys := make([]K, 0, t.size)
+	t.ForEach(func(key K, val V) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+func (t *TreeMap[K, V]) none() (K, V, bool) {
+	var k K
+	var v V
+	return k, v, false
+}
+
+func (t *TreeMap[K, V]) find(key K) *node[K, V] {
+	n := t.root
+	for n != nil {
+		if t.compare(key, n.key) {
+			n = n.left
+		} else if t.compare(n.key, key) {
+			n = n.right
+		} else {
+			return n
+		}
+	}
+	return nil
+}
+
+func (t *TreeMap[K, V]) put(h *node[K, V], key K, val V) *node[K, V] {
+	if h == nil {
+		t.size++
+		return &node[K, V]{key: key, val: val, red: true}
+	}
+	if t.compare(key, h.key) {
+		h.left = t.put(h.left, key, val)
+	} else if t.compare(h.key, key) {
+		h.right = t.put(h.right, key, val)
+	} else {
+		h.val = val
+	}
+	return balance(h)
+}
+
+// delete 调用方需要保证 key 存在
+func (t *TreeMap[K, V]) delete(h *node[K, V], key K) *node[K, V] {
+	if t.compare(key, h.key) {
+		if !isRed(h.left) && !isRed(h.left.left) {
+			h = moveRedLeft(h)
+		}
+		h.left = t.delete(h.left, key)
+	} else {
+		if isRed(h.left) {
+			h = rotateRight(h)
+		}
+		if !t.compare(h.key, key) && h.right == nil {
+			return nil
+		}
+		if !isRed(h.right) && !isRed(h.right.left) {
+			h = moveRedRight(h)
+		}
+		if !t.compare(h.key, key) {
+			// 用右子树的最小节点替换当前节点
+			m := minNode(h.right)
+			h.key, h.val = m.key, m.val
+			h.right = deleteMin(h.right)
+		} else {
+			h.right = t.delete(h.right, key)
+		}
+	}
+	return balance(h)
+}
+
+func (t *TreeMap[K, V]) rangeNode(h *node[K, V], from, to K, fn func(key K, val V) bool) bool {
+	if h == nil {
+		return true
+	}
+	if t.compare(from, h.key) && !t.rangeNode(h.left, from, to, fn) {
+		return false
+	}
+	if !t.compare(h.key, to) {
+		return true
+	}
+	if !t.compare(h.key, from) && !fn(h.key, h.val) {
+		return false
+	}
+	return t.rangeNode(h.right, from, to, fn)
+}
+
+func (t *TreeMap[K, V]) forEach(h *node[K, V], fn func(key K, val V) bool) bool {
+	if h == nil {
+		return true
+	}
+	return t.forEach(h.left, fn) && fn(h.key, h.val) && t.forEach(h.right, fn)
+}
+
+func isRed[K any, V any](h *node[K, V]) bool {
+	return h != nil && h.red
+}
+
+func minNode[K any, V any](h *node[K, V]) *node[K, V] {
+	for h.left != nil {
+		h = h.left
+	}
+	return h
+}
+
+func deleteMin[K any, V any](h *node[K, V]) *node[K, V] {
+	if h.left == nil {
+		return nil
+	}
+	if !isRed(h.left) && !isRed(h.left.left) {
+		h = moveRedLeft(h)
+	}
+	h.left = deleteMin(h.left)
+	return balance(h)
+}
+
+func rotateLeft[K any, V any](h *node[K, V]) *node[K, V] {
+	x := h.right
+	h.right = x.left
+	x.left = h
+	x.red = h.red
+	h.red = true
+	return x
+}
+
+func rotateRight[K any, V any](h *node[K, V]) *node[K, V] {
+	x := h.left
+	h.left = x.right
+	x.right = h
+	x.red = h.red
+	h.red = true
+	return x
+}
+
+func flipColors[K any, V any](h *node[K, V]) {
+	h.red = !h.red
+	h.left.red = !h.left.red
+	h.right.red = !h.right.red
+}
+
+// moveRedLeft 保证 h.left 或者 h.left 的某个子节点是红色
+func moveRedLeft[K any, V any](h *node[K, V]) *node[K, V] {
+	flipColors(h)
+	if isRed(h.right.left) {
+		h.right = rotateRight(h.right)
+		h = rotateLeft(h)
+		flipColors(h)
+	}
+	return h
+}
+
+// moveRedRight 保证 h.right 或者 h.right 的某个子节点是红色
+func moveRedRight[K any, V any](h *node[K, V]) *node[K, V] {
+	flipColors(h)
+	if isRed(h.left.left) {
+		h = rotateRight(h)
+		flipColors(h)
+	}
+	return h
+}
+
+// balance 恢复左倾红黑树的性质
+func balance[K any, V any](h *node[K, V]) *node[K, V] {
+	if isRed(h.right) && !isRed(h.left) {
+		h = rotateLeft(h)
+	}
+	if isRed(h.left) && isRed(h.left.left) {
+		h = rotateRight(h)
+	}
+	if isRed(h.left) && isRed(h.right) {
+		flipColors(h)
+	}
+	return h
+}
@@ -0,0 +1,211 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package tree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeMap_PutGetDelete(t *testing.T) {
+	testCases := []struct {
+		name     string
+		put      []int
+		del      []int
+		wantKeys []int
+	}{
+		{
+			name:     "有序",
+			put:      []int{5, 3, 9, 1, 7},
+			wantKeys: []int{1, 3, 5, 7, 9},
+		},
+		{
+			name:     "覆盖",
+			put:      []int{3, 1, 3, 2, 1},
+			wantKeys: []int{1, 2, 3},
+		},
+		{
+			name:     "删除",
+			put:      []int{5, 3, 9, 1, 7},
+			del:      []int{1, 7, 100},
+			wantKeys: []int{3, 5, 9},
+		},
+		{
+			name:     "全部删除",
+			put:      []int{5, 3, 9},
+			del:      []int{3, 5, 9},
+			wantKeys: []int{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewTreeMap[int, int](less)
+			for _, k := range tc.put {
+				m.Put(k, k*10)
+			}
+			for _, k := range tc.del {
+				m.Delete(k)
+				_, ok := m.Get(k)
+				assert.False(t, ok)
+			}
+			assert.Equal(t, tc.wantKeys, m.Keys())
+			assert.Equal(t, len(tc.wantKeys), m.Len())
+			for _, k := range tc.wantKeys {
+				v, ok := m.Get(k)
+				assert.True(t, ok)
+				assert.Equal(t, k*10, v)
+			}
+		})
+	}
+}
+
+func TestTreeMap_FloorCeiling(t *testing.T) {
+	m := treeMapOf(10, 20, 30, 40)
+	testCases := []struct {
+		name        string
+		key         int
+		wantFloor   int
+		floorOk     bool
+		wantCeiling int
+		ceilingOk   bool
+	}{
+		{name: "小于最小值", key: 5, wantCeiling: 10, ceilingOk: true},
+		{name: "命中", key: 20, wantFloor: 20, floorOk: true, wantCeiling: 20, ceilingOk: true},
+		{name: "中间", key: 25, wantFloor: 20, floorOk: true, wantCeiling: 30, ceilingOk: true},
+		{name: "大于最大值", key: 45, wantFloor: 40, floorOk: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			k, _, ok := m.Floor(tc.key)
+			assert.Equal(t, tc.floorOk, ok)
+			assert.Equal(t, tc.wantFloor, k)
+			k, _, ok = m.Ceiling(tc.key)
+			assert.Equal(t, tc.ceilingOk, ok)
+			assert.Equal(t, tc.wantCeiling, k)
+		})
+	}
+}
+
+func TestTreeMap_MinMax(t *testing.T) {
+	m := NewTreeMap[int, int](less)
+	_, _, ok := m.Min()
+	assert.False(t, ok)
+	_, _, ok = m.Max()
+	assert.False(t, ok)
+	m = treeMapOf(3, 1, 2)
+	k, _, ok := m.Min()
+	assert.True(t, ok)
+	assert.Equal(t, 1, k)
+	k, _, ok = m.Max()
+	assert.True(t, ok)
+	assert.Equal(t, 3, k)
+}
+
+func TestTreeMap_Range(t *testing.T) {
+	m := treeMapOf(10, 20, 30, 40, 50)
+	testCases := []struct {
+		name  string
+		from  int
+		to    int
+		limit int
+		want  []int
+	}{
+		{name: "左闭右开", from: 20, to: 40, want: []int{20, 30}},
+		{name: "边界不在 map 中", from: 15, to: 45, want: []int{20, 30, 40}},
+		{name: "全部", from: 0, to: 100, want: []int{10, 20, 30, 40, 50}},
+		{name: "空区间", from: 31, to: 39, want: []int{}},
+		{name: "提前结束", from: 0, to: 100, limit: 2, want: []int{10, 20}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := make([]int, 0, len(tc.want))
+			m.Range(tc.from, tc.to, func(key int, val int) bool {
+				res = append(res, key)
+				return tc.limit == 0 || len(res) < tc.limit
+			})
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestTreeMap_Random(t *testing.T) {
+	m := NewTreeMap[int, int](less)
+	ref := make(map[int]int)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(500)
+		if r.Intn(3) == 0 {
+			_, ok := m.Delete(k)
+			_, refOk := ref[k]
+			assert.Equal(t, refOk, ok)
+			delete(ref, k)
+		} else {
+			m.Put(k, i)
+			ref[k] = i
+		}
+		require.True(t, isBalanced(m.root))
+	}
+	keys := make([]int, 0, len(ref))
+	for k := range ref {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	assert.Equal(t, keys, m.Keys())
+	for k, v := range ref {
+		val, ok := m.Get(k)
+		assert.True(t, ok)
+		assert.Equal(t, v, val)
+	}
+}
+
+// isBalanced 检查每条从根到叶子的路径上黑色节点数量相同，且没有连续的红色节点
+func isBalanced(root *node[int, int]) bool {
+	var blackHeight func(h *node[int, int]) int
+	blackHeight = func(h *node[int, int]) int {
+		if h == nil {
+			return 0
+		}
+		if isRed(h) && (isRed(h.left) || isRed(h.right)) {
+			return -1
+		}
+		l, r := blackHeight(h.left), blackHeight(h.right)
+		if l < 0 || l != r {
+			return -1
+		}
+		if !h.red {
+			l++
+		}
+		return l
+	}
+	return !isRed(root) && blackHeight(root) >= 0
+}
+
+func treeMapOf(keys ...int) *TreeMap[int, int] {
+	m := NewTreeMap[int, int](less)
+	for _, k := range keys {
+		m.Put(k, k)
+	}
+	return m
+}
+
+func less(a, b int) bool {
+	return a < b
+}
@@ -0,0 +1,196 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package trie
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Match 一次匹配的结果，Start 和 End 是 text 中的字节下标，text[Start:End] == Keyword
+type Match struct {
+	Keyword string
+	Start   int
+	End     int
+}
+
+// ACTrie 基于 Aho-Corasick 自动机的多关键词匹配，一次遍历就能找出所有关键词
+// 适合敏感词过滤、日志脱敏等场景
+// 构建之后不可修改，可以在多个 goroutine 中并发使用
+type ACTrie struct {
+	root     *acNode
+	keywords []string
+}
+
+type acNode struct {
+	children map[rune]*acNode
+	// fail 匹配失败时跳转的节点，即当前路径的最长后缀对应的节点
+	fail *acNode
+	// outputs 以当前节点结尾的所有关键词下标，包括 fail 链上的
+	outputs []int
+}
+
+func newACNode() *acNode {
+	return &acNode{
+		children: make(map[rune]*acNode),
+	}
+}
+
+// NewACTrie 根据关键词构建自动机，空字符串会被忽略
+func NewACTrie(keywords []string) *ACTrie {
+	t := &ACTrie{
+		root:     newACNode(),
+		keywords: make([]string, 0, len(keywords)),
+	}
+	for _, kw := range keywords {
+		if kw == "" {
+			continue
+		}
+		n := t.root
+		for _, r := range kw {
+			child, ok := n.children[r]
+			if !ok {
+				child = newACNode()
+				n.children[r] = child
+			}
+			n = child
+		}
+		n.outputs = append(n.outputs, len(t.keywords))
+		t.keywords = append(t.keywords, kw)
+	}
+	t.buildFail()
+	return t
+}
+
+// buildFail 按层遍历，子节点的 fail 由父节点的 fail 推导出来
+func (t *ACTrie) buildFail() {
+	queue := make([]*acNode, 0, len(t.root.children))
+	for _, child := range t.root.children {
+		child.fail = t.root
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+		for r, child := range n.children {
+			fail := n.fail
+			for fail != nil {
+				if next, ok := fail.children[r]; ok {
+					child.fail = next
+					break
+				}
+				fail = fail.fail
+			}
+			if child.fail == nil {
+				child.fail = t.root
+			}
+			child.outputs = append(child.outputs, child.fail.outputs...)
+			queue = append(queue, child)
+		}
+	}
+}
+
+// next 从 n 出发接收字符 r 之后到达的节点
+func (t *ACTrie) next(n *acNode, r rune) *acNode {
+	for n != t.root {
+		if child, ok := n.children[r]; ok {
+			return child
+		}
+		n = n.fail
+	}
+	if child, ok := n.children[r]; ok {
+		return child
+	}
+	return t.root
+}
+
+// FindAll 返回 text 中所有的匹配，按照结束位置排序，重叠的匹配都会返回
+func (t *ACTrie) FindAll(text string) []Match {
+	var res []Match
+	n := t.root
+	for i := 0; i < len(text); {
+		// 非法的 UTF-8 字节解析成 RuneError，宽度是 1 而不是 RuneError 编码之后的 3
+		r, size := utf8.DecodeRuneInString(text[i:])
+		n = t.next(n, r)
+		i += size
+		for _, idx := range n.outputs {
+			if m, ok := t.match(text, i, idx); ok {
+				res = append(res, m)
+			}
+		}
+	}
+	return res
+}
+
+// match 确认 text 中以 end 结尾的部分就是第 idx 个关键词
+// 不同的非法字节都会解析成 RuneError，所以自动机匹配之后还需要按字节确认一遍
+func (t *ACTrie) match(text string, end int, idx int) (Match, bool) {
+	kw := t.keywords[idx]
+	start := end - len(kw)
+	if start < 0 || text[start:end] != kw {
+		return Match{}, false
+	}
+	return Match{
+		Keyword: kw,
+		Start:   start,
+		End:     end,
+	}, true
+}
+
+// Contains 判断 text 中是否包含任意一个关键词
+func (t *ACTrie) Contains(text string) bool {
+	n := t.root
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		n = t.next(n, r)
+		i += size
+		for _, idx := range n.outputs {
+			if _, ok := t.match(text, i, idx); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Replace 把 text 中所有匹配到的关键词的每个字符都替换成 mask
+func (t *ACTrie) Replace(text string, mask rune) string {
+	matches := t.FindAll(text)
+	if len(matches) == 0 {
+		return text
+	}
+	// 标记需要替换的字节，重叠的匹配自然合并
+	covered := make([]bool, len(text))
+	for _, m := range matches {
+		for i := m.Start; i < m.End; i++ {
+			covered[i] = true
+		}
+	}
+	var sb strings.Builder
+	sb.Grow(len(text))
+	for i := 0; i < len(text); {
+		_, size := utf8.DecodeRuneInString(text[i:])
+		if covered[i] {
+			sb.WriteRune(mask)
+		} else {
+			// 原样写入，非法的字节不会变成 RuneError
+			sb.WriteString(text[i : i+size])
+		}
+		i += size
+	}
+	return sb.String()
+}
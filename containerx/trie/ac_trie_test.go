@@ -0,0 +1,147 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package trie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACTrie_FindAll(t *testing.T) {
+	testCases := []struct {
+		name     string
+		keywords []string
+		text     string
+		want     []Match
+	}{
+		{
+			name:     "经典用例",
+			keywords: []string{"he", "she", "his", "hers"},
+			text:     "ushers",
+			want: []Match{
+				{Keyword: "she", Start: 1, End: 4},
+				{Keyword: "he", Start: 2, End: 4},
+				{Keyword: "hers", Start: 2, End: 6},
+			},
+		},
+		{
+			name:     "中文",
+			keywords: []string{"敏感", "敏感词", "词"},
+			text:     "这是敏感词。",
+			want: []Match{
+				{Keyword: "敏感", Start: 6, End: 12},
+				{Keyword: "敏感词", Start: 6, End: 15},
+				{Keyword: "词", Start: 12, End: 15},
+			},
+		},
+		{
+			name:     "没有匹配",
+			keywords: []string{"abc"},
+			text:     "abdabd",
+		},
+		{
+			name:     "空关键词",
+			keywords: []string{""},
+			text:     "abc",
+		},
+		{
+			name:     "非法的 UTF-8",
+			keywords: []string{"a\xffb", "b"},
+			text:     "a\xffb\xfeb",
+			want: []Match{
+				{Keyword: "a\xffb", Start: 0, End: 3},
+				{Keyword: "b", Start: 2, End: 3},
+				{Keyword: "b", Start: 4, End: 5},
+			},
+		},
+		{
+			name:     "不同的非法字节不匹配",
+			keywords: []string{"a\xffb"},
+			text:     "a\xfeb",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewACTrie(tc.keywords)
+			res := ac.FindAll(tc.text)
+			assert.Equal(t, tc.want, res)
+			assert.Equal(t, len(tc.want) > 0, ac.Contains(tc.text))
+			for _, m := range res {
+				assert.Equal(t, m.Keyword, tc.text[m.Start:m.End])
+			}
+		})
+	}
+}
+
+func TestACTrie_Replace(t *testing.T) {
+	testCases := []struct {
+		name     string
+		keywords []string
+		text     string
+		want     string
+	}{
+		{
+			name:     "重叠的匹配合并",
+			keywords: []string{"she", "hers"},
+			text:     "ushers",
+			want:     "u*****",
+		},
+		{
+			name:     "中文按字符替换",
+			keywords: []string{"敏感词"},
+			text:     "这是敏感词。",
+			want:     "这是***。",
+		},
+		{
+			name:     "脱敏密钥",
+			keywords: []string{"secret-token"},
+			text:     "token=secret-token&user=1",
+			want:     "token=************&user=1",
+		},
+		{
+			name:     "没有匹配",
+			keywords: []string{"abc"},
+			text:     "abd",
+			want:     "abd",
+		},
+		{
+			name:     "非法的 UTF-8 原样保留",
+			keywords: []string{"key"},
+			text:     "\xffkey\xfe",
+			want:     "\xff***\xfe",
+		},
+		{
+			name:     "关键词包含非法的 UTF-8",
+			keywords: []string{"\xffa"},
+			text:     "\xfea\xffa",
+			want:     "\xfea**",
+		},
+		{
+			name:     "RuneError 不匹配非法字节",
+			keywords: []string{"\uFFFDa"},
+			text:     "\xffa\uFFFDa",
+			want:     "\xffa**",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewACTrie(tc.keywords)
+			assert.Equal(t, tc.want, ac.Replace(tc.text, '*'))
+		})
+	}
+}
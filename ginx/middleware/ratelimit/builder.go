@@ -18,7 +18,10 @@ package ratelimit
 
 import (
 	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -45,7 +48,13 @@ func (b *MiddlewareBuilder) Prefix(prefix string) *MiddlewareBuilder {
 	return b
 }
 
+// Build 构建限流中间件
+// limiter 实现了 ratelimit.DecisionLimiter 时，响应中会带上 X-RateLimit-Limit、X-RateLimit-Remaining、
+// X-RateLimit-Reset（配额完全恢复的秒数），被限流时还会带上 Retry-After（可以重试的秒数）
 func (b *MiddlewareBuilder) Build() gin.HandlerFunc {
+	if limiter, ok := b.limiter.(ratelimit.DecisionLimiter); ok {
+		return b.buildWithDecision(limiter)
+	}
 	return func(ctx *gin.Context) {
 		limited, err := b.limit(ctx)
 		if err != nil {
@@ -63,6 +72,35 @@ func (b *MiddlewareBuilder) Build() gin.HandlerFunc {
 }
 
 func (b *MiddlewareBuilder) limit(ctx *gin.Context) (bool, error) {
-	key := fmt.Sprintf("%s:%s", b.prefix, ctx.ClientIP())
-	return b.limiter.Limit(ctx.Request.Context(), key)
+	return b.limiter.Limit(ctx.Request.Context(), b.key(ctx))
+}
+
+func (b *MiddlewareBuilder) key(ctx *gin.Context) string {
+	return fmt.Sprintf("%s:%s", b.prefix, ctx.ClientIP())
+}
+
+func (b *MiddlewareBuilder) buildWithDecision(limiter ratelimit.DecisionLimiter) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		d, err := limiter.Allow(ctx.Request.Context(), b.key(ctx))
+		if err != nil {
+			b.l.Error("err from limit", logger.Error(err))
+			ctx.AbortWithStatus(http.StatusInternalServerError)
+			return
+		}
+		ctx.Header("X-RateLimit-Limit", strconv.FormatInt(d.Limit, 10))
+		ctx.Header("X-RateLimit-Remaining", strconv.FormatInt(d.Remaining, 10))
+		ctx.Header("X-RateLimit-Reset", seconds(d.ResetAfter))
+		if !d.Allowed {
+			b.l.Warn("has been limited", logger.String("ip", ctx.ClientIP()))
+			ctx.Header("Retry-After", seconds(d.RetryAfter))
+			ctx.AbortWithStatus(http.StatusTooManyRequests)
+			return
+		}
+		ctx.Next()
+	}
+}
+
+// seconds 向上取整成秒，避免客户端过早重试
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
 }
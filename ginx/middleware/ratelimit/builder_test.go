@@ -0,0 +1,139 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/wkRonin/toolkit/logger"
+	"github.com/wkRonin/toolkit/ratelimit"
+	limitmocks "github.com/wkRonin/toolkit/ratelimit/mocks"
+)
+
+func TestMiddlewareBuilder_Build(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mock       func(ctrl *gomock.Controller) ratelimit.Limiter
+		wantCode   int
+		wantHeader http.Header
+	}{
+		{
+			name: "放行",
+			mock: func(ctrl *gomock.Controller) ratelimit.Limiter {
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				limiter.EXPECT().Limit(gomock.Any(), "ip-limiter:192.0.2.1").Return(false, nil)
+				return limiter
+			},
+			wantCode:   http.StatusOK,
+			wantHeader: http.Header{},
+		},
+		{
+			name: "限流",
+			mock: func(ctrl *gomock.Controller) ratelimit.Limiter {
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				limiter.EXPECT().Limit(gomock.Any(), "ip-limiter:192.0.2.1").Return(true, nil)
+				return limiter
+			},
+			wantCode:   http.StatusTooManyRequests,
+			wantHeader: http.Header{},
+		},
+		{
+			name: "限流器错误",
+			mock: func(ctrl *gomock.Controller) ratelimit.Limiter {
+				limiter := limitmocks.NewMockLimiter(ctrl)
+				limiter.EXPECT().Limit(gomock.Any(), "ip-limiter:192.0.2.1").Return(false, errors.New("mock error"))
+				return limiter
+			},
+			wantCode:   http.StatusInternalServerError,
+			wantHeader: http.Header{},
+		},
+		{
+			name: "放行并返回配额",
+			mock: func(ctrl *gomock.Controller) ratelimit.Limiter {
+				limiter := limitmocks.NewMockDecisionLimiter(ctrl)
+				limiter.EXPECT().Allow(gomock.Any(), "ip-limiter:192.0.2.1").Return(ratelimit.Decision{
+					Allowed:    true,
+					Limit:      100,
+					Remaining:  99,
+					ResetAfter: 1500 * time.Millisecond,
+				}, nil)
+				return limiter
+			},
+			wantCode: http.StatusOK,
+			wantHeader: http.Header{
+				"X-Ratelimit-Limit":     []string{"100"},
+				"X-Ratelimit-Remaining": []string{"99"},
+				"X-Ratelimit-Reset":     []string{"2"},
+			},
+		},
+		{
+			name: "限流并返回重试时间",
+			mock: func(ctrl *gomock.Controller) ratelimit.Limiter {
+				limiter := limitmocks.NewMockDecisionLimiter(ctrl)
+				limiter.EXPECT().Allow(gomock.Any(), "ip-limiter:192.0.2.1").Return(ratelimit.Decision{
+					Limit:      100,
+					ResetAfter: 10 * time.Second,
+					RetryAfter: 200 * time.Millisecond,
+				}, nil)
+				return limiter
+			},
+			wantCode: http.StatusTooManyRequests,
+			wantHeader: http.Header{
+				"X-Ratelimit-Limit":     []string{"100"},
+				"X-Ratelimit-Remaining": []string{"0"},
+				"X-Ratelimit-Reset":     []string{"10"},
+				"Retry-After":           []string{"1"},
+			},
+		},
+		{
+			name: "DecisionLimiter 错误",
+			mock: func(ctrl *gomock.Controller) ratelimit.Limiter {
+				limiter := limitmocks.NewMockDecisionLimiter(ctrl)
+				limiter.EXPECT().Allow(gomock.Any(), "ip-limiter:192.0.2.1").
+					Return(ratelimit.Decision{}, errors.New("mock error"))
+				return limiter
+			},
+			wantCode:   http.StatusInternalServerError,
+			wantHeader: http.Header{},
+		},
+	}
+	gin.SetMode(gin.ReleaseMode)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			server := gin.New()
+			server.Use(NewMiddlewareBuilder(tc.mock(ctrl), &logger.NopLogger{}).Build())
+			server.GET("/hello", func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/hello", nil)
+			recorder := httptest.NewRecorder()
+			server.ServeHTTP(recorder, req)
+			assert.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantHeader, recorder.Header())
+		})
+	}
+}
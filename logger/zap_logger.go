@@ -17,13 +17,18 @@
 package logger
 
 import (
+	"fmt"
+
 	"go.uber.org/zap"
 
+	"github.com/wkRonin/toolkit/containerx/trie"
 	"github.com/wkRonin/toolkit/zapx"
 )
 
 type ZapLogger struct {
 	logger *zap.Logger
+	// secrets 需要脱敏的关键词，为 nil 时不做处理
+	secrets *trie.ACTrie
 }
 
 func NewZapLogger(l *zap.Logger) Logger {
@@ -32,20 +37,28 @@ func NewZapLogger(l *zap.Logger) Logger {
 	}
 }
 
+// NewZapLoggerWithSecrets 日志消息和字符串、error、fmt.Stringer 类型的字段中出现 secrets 时会被替换成 *
+func NewZapLoggerWithSecrets(l *zap.Logger, secrets []string) Logger {
+	return &ZapLogger{
+		logger:  l,
+		secrets: trie.NewACTrie(secrets),
+	}
+}
+
 func (z *ZapLogger) Debug(msg string, args ...Field) {
-	z.logger.Debug(msg, z.toArgs(args)...)
+	z.logger.Debug(z.mask(msg), z.toArgs(args)...)
 }
 
 func (z *ZapLogger) Info(msg string, args ...Field) {
-	z.logger.Info(msg, z.toArgs(args)...)
+	z.logger.Info(z.mask(msg), z.toArgs(args)...)
 }
 
 func (z *ZapLogger) Warn(msg string, args ...Field) {
-	z.logger.Warn(msg, z.toArgs(args)...)
+	z.logger.Warn(z.mask(msg), z.toArgs(args)...)
 }
 
 func (z *ZapLogger) Error(msg string, args ...Field) {
-	z.logger.Error(msg, z.toArgs(args)...)
+	z.logger.Error(z.mask(msg), z.toArgs(args)...)
 }
 
 func (z *ZapLogger) toArgs(args []Field) []zap.Field {
@@ -56,7 +69,39 @@ func (z *ZapLogger) toArgs(args []Field) []zap.Field {
 			res = append(res, zap.Any(newAr.Key, newAr.String))
 			continue
 		}
+		if z.secrets != nil {
+			if newAr, ok := z.maskField(ar); ok {
+				res = append(res, zap.Any(newAr.Key, newAr.String))
+				continue
+			}
+		}
 		res = append(res, zap.Any(ar.Key, ar.Value))
 	}
 	return res
 }
+
+// maskField 对字符串、error 和 fmt.Stringer 类型的字段脱敏
+// 没有命中 secrets 或者是其他类型时返回 false，保留原来的字段类型，比如 time.Duration 依然按照数字记录
+func (z *ZapLogger) maskField(ar Field) (zap.Field, bool) {
+	var str string
+	switch val := ar.Value.(type) {
+	case string:
+		str = val
+	case error, fmt.Stringer:
+		// fmt.Sprint 可以处理 nil 指针和方法 panic 的情况
+		str = fmt.Sprint(val)
+	default:
+		return zap.Field{}, false
+	}
+	if !z.secrets.Contains(str) {
+		return zap.Field{}, false
+	}
+	return zapx.MaskSecrets(ar.Key, str, z.secrets), true
+}
+
+func (z *ZapLogger) mask(msg string) string {
+	if z.secrets == nil {
+		return msg
+	}
+	return z.secrets.Replace(msg, '*')
+}
@@ -0,0 +1,92 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type token string
+
+func (t token) String() string {
+	return "token=" + string(t)
+}
+
+func TestZapLogger_Secrets(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := NewZapLoggerWithSecrets(zap.New(core), []string{"abc123"})
+	l.Error("登录失败 abc123",
+		String("token", "Bearer abc123"),
+		Error(errors.New("invalid token abc123")),
+		Any("auth", token("abc123")),
+		Int64("uid", 123),
+		String("phone", "13800001111"))
+
+	entries := logs.All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "登录失败 ******", entries[0].Message)
+	assert.Equal(t, map[string]any{
+		"token": "Bearer ******",
+		"error": "invalid token ******",
+		"auth":  "token=******",
+		"uid":   int64(123),
+		"phone": "138****1111",
+	}, entries[0].ContextMap())
+}
+
+func TestZapLogger_SecretsNotMatched(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := NewZapLoggerWithSecrets(zap.New(core), []string{"abc123"})
+	now := time.Unix(1000, 0)
+	l.Info("ok",
+		Any("cost", time.Second),
+		Any("time", now),
+		Any("auth", token("xyz")))
+
+	entries := logs.All()
+	assert.Len(t, entries, 1)
+	// 没有命中的时候保留原来的字段类型
+	assert.Equal(t, map[string]any{
+		"cost": time.Second,
+		"time": now,
+		"auth": "token=xyz",
+	}, entries[0].ContextMap())
+	assert.Equal(t, zapcore.DurationType, entries[0].Context[0].Type)
+	assert.Equal(t, zapcore.TimeType, entries[0].Context[1].Type)
+	assert.Equal(t, zapcore.StringerType, entries[0].Context[2].Type)
+}
+
+func TestZapLogger_NoSecrets(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := NewZapLogger(zap.New(core))
+	l.Info("abc123", String("token", "abc123"), Error(errors.New("abc123")))
+
+	entries := logs.All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "abc123", entries[0].Message)
+	assert.Equal(t, map[string]any{
+		"token": "abc123",
+		"error": "abc123",
+	}, entries[0].ContextMap())
+}
@@ -0,0 +1,74 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+type fixedWindow struct {
+	start time.Time
+	cnt   int
+}
+
+// LocalFixedWindowLimiter 单机的固定窗口计数限流，和 RedisFixedWindowLimiter 的语义一致
+type LocalFixedWindowLimiter struct {
+	store    *localStore[fixedWindow]
+	interval time.Duration
+	rate     int
+}
+
+// NewLocalFixedWindowLimiter 创建固定窗口限流器，interval 为窗口大小，rate 为窗口内允许的请求数
+// interval 或者 rate 不大于 0 时返回 ErrInvalidArgument
+func NewLocalFixedWindowLimiter(interval time.Duration, rate int, opts ...LocalOption) (DecisionLimiter, error) {
+	if interval <= 0 || rate <= 0 {
+		return nil, ErrInvalidArgument
+	}
+	return &LocalFixedWindowLimiter{
+		store:    newLocalStore[fixedWindow](interval, opts),
+		interval: interval,
+		rate:     rate,
+	}, nil
+}
+
+func (l *LocalFixedWindowLimiter) Limit(ctx context.Context, key string) (bool, error) {
+	d, err := l.Allow(ctx, key)
+	return !d.Allowed, err
+}
+
+func (l *LocalFixedWindowLimiter) Allow(ctx context.Context, key string) (Decision, error) {
+	return l.store.allow(key, func(w *fixedWindow, now time.Time) Decision {
+		if now.Sub(w.start) >= l.interval {
+			// 窗口内的第一个请求，窗口从现在开始
+			w.start = now
+			w.cnt = 0
+		}
+		w.cnt++
+		d := Decision{
+			Limit:      int64(l.rate),
+			ResetAfter: w.start.Add(l.interval).Sub(now),
+		}
+		if w.cnt > l.rate {
+			d.RetryAfter = d.ResetAfter
+			return d
+		}
+		d.Allowed = true
+		d.Remaining = int64(l.rate - w.cnt)
+		return d
+	}), nil
+}
@@ -0,0 +1,73 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// LocalSlidingWindowLimiter 单机的滑动窗口限流，和 RedisSlidingWindowLimiter 的语义一致
+type LocalSlidingWindowLimiter struct {
+	store    *localStore[[]time.Time]
+	interval time.Duration
+	rate     int
+}
+
+// NewLocalSlidingWindowLimiter 创建滑动窗口限流器，interval 为窗口大小，rate 为窗口内允许的请求数
+// interval 或者 rate 不大于 0 时返回 ErrInvalidArgument
+func NewLocalSlidingWindowLimiter(interval time.Duration, rate int, opts ...LocalOption) (DecisionLimiter, error) {
+	if interval <= 0 || rate <= 0 {
+		return nil, ErrInvalidArgument
+	}
+	return &LocalSlidingWindowLimiter{
+		store:    newLocalStore[[]time.Time](interval, opts),
+		interval: interval,
+		rate:     rate,
+	}, nil
+}
+
+func (l *LocalSlidingWindowLimiter) Limit(ctx context.Context, key string) (bool, error) {
+	d, err := l.Allow(ctx, key)
+	return !d.Allowed, err
+}
+
+func (l *LocalSlidingWindowLimiter) Allow(ctx context.Context, key string) (Decision, error) {
+	return l.store.allow(key, func(reqs *[]time.Time, now time.Time) Decision {
+		// 移除窗口之外的请求
+		start := now.Add(-l.interval)
+		i := 0
+		for i < len(*reqs) && !(*reqs)[i].After(start) {
+			i++
+		}
+		*reqs = (*reqs)[i:]
+		d := Decision{Limit: int64(l.rate)}
+		if len(*reqs) >= l.rate {
+			if len(*reqs) > 0 {
+				// 最早的请求滑出窗口之后才能重试
+				d.RetryAfter = (*reqs)[0].Sub(start)
+				d.ResetAfter = (*reqs)[len(*reqs)-1].Sub(start)
+			}
+			return d
+		}
+		*reqs = append(*reqs, now)
+		d.Allowed = true
+		d.Remaining = int64(l.rate - len(*reqs))
+		d.ResetAfter = l.interval
+		return d
+	}), nil
+}
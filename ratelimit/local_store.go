@@ -0,0 +1,149 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"container/list"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// localShards 分片的数量，减少不同 key 之间的锁竞争
+const localShards = 16
+
+// defaultMaxKeys 默认最多保存的 key 的数量
+const defaultMaxKeys = 1 << 16
+
+type localOptions struct {
+	maxKeys int
+}
+
+type LocalOption func(o *localOptions)
+
+// WithMaxKeys 最多保存 maxKeys 个 key 的限流状态，默认 65536
+// 超过之后淘汰最久没有访问的 key，被淘汰的 key 下次访问时从初始状态开始计算
+func WithMaxKeys(maxKeys int) LocalOption {
+	return func(o *localOptions) {
+		o.maxKeys = maxKeys
+	}
+}
+
+type localEntry[S any] struct {
+	key        string
+	state      S
+	lastAccess time.Time
+}
+
+// localShard 按照访问时间排序的 key，最近访问的在链表头部
+type localShard[S any] struct {
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// localStore 保存每个 key 的限流状态，超过 idle 没有访问的 key 会被清理
+// 各个限流器的 idle 都是状态恢复到初始值需要的时间，所以清理不会影响限流结果
+// 清理在访问的时候顺带进行，只检查链表尾部最久没有访问的 key，不需要遍历也不需要额外的 goroutine
+type localStore[S any] struct {
+	shards []*localShard[S]
+	idle   time.Duration
+	// maxKeys 每个分片最多保存的 key 的数量
+	maxKeys int
+	now     func() time.Time
+}
+
+func newLocalStore[S any](idle time.Duration, opts []LocalOption) *localStore[S] {
+	o := &localOptions{
+		maxKeys: defaultMaxKeys,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	shards := localShards
+	if o.maxKeys < shards {
+		shards = 1
+	}
+	s := &localStore[S]{
+		shards: make([]*localShard[S], shards),
+		idle:   idle,
+		// 向上取整，保证每个分片至少能保存一个 key
+		maxKeys: (o.maxKeys + shards - 1) / shards,
+		now:     time.Now,
+	}
+	if s.maxKeys < 1 {
+		s.maxKeys = 1
+	}
+	for i := range s.shards {
+		s.shards[i] = &localShard[S]{
+			entries: make(map[string]*list.Element),
+			lru:     list.New(),
+		}
+	}
+	return s
+}
+
+func (s *localStore[S]) shard(key string) *localShard[S] {
+	if len(s.shards) == 1 {
+		return s.shards[0]
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// allow 在锁的保护下用 fn 更新 key 的状态，新的 key 拿到的是 S 的零值
+func (s *localStore[S]) allow(key string, fn func(state *S, now time.Time) Decision) Decision {
+	sh := s.shard(key)
+	sh.mutex.Lock()
+	defer sh.mutex.Unlock()
+	now := s.now()
+	var e *localEntry[S]
+	if elem, ok := sh.entries[key]; ok {
+		sh.lru.MoveToFront(elem)
+		e = elem.Value.(*localEntry[S])
+	} else {
+		e = &localEntry[S]{key: key}
+		sh.entries[key] = sh.lru.PushFront(e)
+	}
+	e.lastAccess = now
+	s.evict(sh, now)
+	return fn(&e.state, now)
+}
+
+// evict 从链表尾部开始淘汰空闲的 key 和超出容量的 key，当前访问的 key 在头部，不会被淘汰
+func (s *localStore[S]) evict(sh *localShard[S], now time.Time) {
+	for sh.lru.Len() > 1 {
+		back := sh.lru.Back()
+		e := back.Value.(*localEntry[S])
+		if sh.lru.Len() <= s.maxKeys && now.Sub(e.lastAccess) < s.idle {
+			return
+		}
+		sh.lru.Remove(back)
+		delete(sh.entries, e.key)
+	}
+}
+
+func (s *localStore[S]) len() int {
+	res := 0
+	for _, sh := range s.shards {
+		sh.mutex.Lock()
+		res += len(sh.entries)
+		sh.mutex.Unlock()
+	}
+	return res
+}
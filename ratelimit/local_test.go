@@ -0,0 +1,276 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// step 一次限流调用，先把时间往前拨 advance 再调用
+type step struct {
+	advance time.Duration
+	key     string
+	want    bool
+}
+
+func runSteps(t *testing.T, l Limiter, now *time.Time, steps []step) {
+	for i, s := range steps {
+		*now = now.Add(s.advance)
+		key := s.key
+		if key == "" {
+			key = "key"
+		}
+		limited, err := l.Limit(context.Background(), key)
+		require.NoError(t, err)
+		assert.Equal(t, s.want, limited, "第 %d 步", i)
+	}
+}
+
+func TestLocalLimiter(t *testing.T) {
+	testCases := []struct {
+		name    string
+		limiter func(now func() time.Time) Limiter
+		steps   []step
+	}{
+		{
+			name: "滑动窗口",
+			limiter: func(now func() time.Time) Limiter {
+				limiter, err := NewLocalSlidingWindowLimiter(time.Second, 2)
+				require.NoError(t, err)
+				l := limiter.(*LocalSlidingWindowLimiter)
+				l.store.now = now
+				return l
+			},
+			steps: []step{
+				{want: false},
+				{advance: 500 * time.Millisecond, want: false},
+				{advance: 400 * time.Millisecond, want: true},
+				// 其他 key 不受影响
+				{key: "other", want: false},
+				// 第一个请求滑出窗口
+				{advance: 100 * time.Millisecond, want: false},
+				{want: true},
+			},
+		},
+		{
+			name: "固定窗口",
+			limiter: func(now func() time.Time) Limiter {
+				limiter, err := NewLocalFixedWindowLimiter(time.Second, 2)
+				require.NoError(t, err)
+				l := limiter.(*LocalFixedWindowLimiter)
+				l.store.now = now
+				return l
+			},
+			steps: []step{
+				{want: false},
+				{advance: 500 * time.Millisecond, want: false},
+				{advance: 400 * time.Millisecond, want: true},
+				{key: "other", want: false},
+				// 新的窗口
+				{advance: 100 * time.Millisecond, want: false},
+				{want: false},
+				{want: true},
+			},
+		},
+		{
+			name: "令牌桶",
+			limiter: func(now func() time.Time) Limiter {
+				limiter, err := NewLocalTokenBucketLimiter(3, 10)
+				require.NoError(t, err)
+				l := limiter.(*LocalTokenBucketLimiter)
+				l.store.now = now
+				return l
+			},
+			steps: []step{
+				// 突发流量
+				{want: false},
+				{want: false},
+				{want: false},
+				{want: true},
+				{key: "other", want: false},
+				// 100ms 生成一个令牌
+				{advance: 100 * time.Millisecond, want: false},
+				{want: true},
+				// 补充的令牌不超过容量
+				{advance: time.Minute, want: false},
+				{want: false},
+				{want: false},
+				{want: true},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Unix(1000, 0)
+			l := tc.limiter(func() time.Time {
+				return now
+			})
+			runSteps(t, l, &now, tc.steps)
+		})
+	}
+}
+
+func TestLocalStore_Evict(t *testing.T) {
+	now := time.Unix(1000, 0)
+	// key 的数量上限小于分片数时只有一个分片
+	limiter, err := NewLocalFixedWindowLimiter(time.Second, 1, WithMaxKeys(10))
+	require.NoError(t, err)
+	l := limiter.(*LocalFixedWindowLimiter)
+	l.store.now = func() time.Time {
+		return now
+	}
+	for i := 0; i < 10; i++ {
+		_, err := l.Limit(context.Background(), strconv.Itoa(i))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 10, l.store.len())
+
+	// 空闲超过一个窗口的 key 被清理，只剩下当前访问的 key
+	now = now.Add(time.Second)
+	limited, err := l.Limit(context.Background(), "0")
+	require.NoError(t, err)
+	assert.False(t, limited)
+	assert.Equal(t, 1, l.store.len())
+}
+
+func TestLocalStore_MaxKeys(t *testing.T) {
+	now := time.Unix(1000, 0)
+	limiter, err := NewLocalFixedWindowLimiter(time.Second, 1, WithMaxKeys(10))
+	require.NoError(t, err)
+	l := limiter.(*LocalFixedWindowLimiter)
+	l.store.now = func() time.Time {
+		return now
+	}
+	for i := 0; i < 20; i++ {
+		_, err := l.Limit(context.Background(), strconv.Itoa(i))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 10, l.store.len())
+
+	// 最近访问的 key 还在
+	limited, err := l.Limit(context.Background(), "19")
+	require.NoError(t, err)
+	assert.True(t, limited)
+	// 最久没有访问的 key 被淘汰，重新从初始状态开始
+	limited, err = l.Limit(context.Background(), "0")
+	require.NoError(t, err)
+	assert.False(t, limited)
+	assert.Equal(t, 10, l.store.len())
+
+	// 默认按照 key 分片，总数不超过上限
+	limiter, err = NewLocalFixedWindowLimiter(time.Second, 1, WithMaxKeys(160))
+	require.NoError(t, err)
+	sharded := limiter.(*LocalFixedWindowLimiter)
+	sharded.store.now = l.store.now
+	for i := 0; i < 1000; i++ {
+		_, err = sharded.Limit(context.Background(), strconv.Itoa(i))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, localShards, len(sharded.store.shards))
+	assert.LessOrEqual(t, sharded.store.len(), 160)
+}
+
+func TestNewLocalWindowLimiter(t *testing.T) {
+	_, err := NewLocalSlidingWindowLimiter(0, 10)
+	assert.Equal(t, ErrInvalidArgument, err)
+	_, err = NewLocalSlidingWindowLimiter(time.Second, 0)
+	assert.Equal(t, ErrInvalidArgument, err)
+	_, err = NewLocalFixedWindowLimiter(-time.Second, 10)
+	assert.Equal(t, ErrInvalidArgument, err)
+	_, err = NewLocalFixedWindowLimiter(time.Second, -1)
+	assert.Equal(t, ErrInvalidArgument, err)
+}
+
+func TestNewLocalTokenBucketLimiter(t *testing.T) {
+	_, err := NewLocalTokenBucketLimiter(0, 10)
+	assert.Equal(t, ErrInvalidArgument, err)
+	_, err = NewLocalTokenBucketLimiter(10, 0)
+	assert.Equal(t, ErrInvalidArgument, err)
+	_, err = NewLocalTokenBucketLimiter(10, -1)
+	assert.Equal(t, ErrInvalidArgument, err)
+}
+
+func TestLocalLimiter_Allow(t *testing.T) {
+	now := time.Unix(1000, 0)
+	nowFunc := func() time.Time {
+		return now
+	}
+	limiter, err := NewLocalSlidingWindowLimiter(time.Second, 2)
+	require.NoError(t, err)
+	sliding := limiter.(*LocalSlidingWindowLimiter)
+	sliding.store.now = nowFunc
+	limiter, err = NewLocalFixedWindowLimiter(time.Second, 2)
+	require.NoError(t, err)
+	fixed := limiter.(*LocalFixedWindowLimiter)
+	fixed.store.now = nowFunc
+	limiter, err = NewLocalTokenBucketLimiter(2, 2)
+	require.NoError(t, err)
+	bucket := limiter.(*LocalTokenBucketLimiter)
+	bucket.store.now = nowFunc
+
+	testCases := []struct {
+		name    string
+		limiter DecisionLimiter
+		want    []Decision
+	}{
+		{
+			name:    "滑动窗口",
+			limiter: sliding,
+			want: []Decision{
+				{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: time.Second},
+				{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: time.Second},
+				{Limit: 2, ResetAfter: 800 * time.Millisecond, RetryAfter: 600 * time.Millisecond},
+			},
+		},
+		{
+			name:    "固定窗口",
+			limiter: fixed,
+			want: []Decision{
+				{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: time.Second},
+				{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: 800 * time.Millisecond},
+				{Limit: 2, ResetAfter: 600 * time.Millisecond, RetryAfter: 600 * time.Millisecond},
+			},
+		},
+		{
+			name:    "令牌桶",
+			limiter: bucket,
+			want: []Decision{
+				{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: 500 * time.Millisecond},
+				// 200ms 生成 0.4 个令牌
+				{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: 800 * time.Millisecond},
+				{Limit: 2, ResetAfter: 600 * time.Millisecond, RetryAfter: 100 * time.Millisecond},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now = time.Unix(1000, 0)
+			for i, want := range tc.want {
+				d, err := tc.limiter.Allow(context.Background(), "key")
+				require.NoError(t, err)
+				assert.Equal(t, want, d, "第 %d 次", i)
+				now = now.Add(200 * time.Millisecond)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+type tokenBucket struct {
+	tokens float64
+	ts     time.Time
+}
+
+// LocalTokenBucketLimiter 单机的令牌桶限流，和 RedisTokenBucketLimiter 的语义一致
+type LocalTokenBucketLimiter struct {
+	store    *localStore[tokenBucket]
+	capacity int
+	// rate 每秒生成的令牌数
+	rate float64
+}
+
+// NewLocalTokenBucketLimiter 创建令牌桶限流器，capacity 为桶的容量，rate 为每秒生成的令牌数
+// capacity 或者 rate 不大于 0 时返回 ErrInvalidArgument
+func NewLocalTokenBucketLimiter(capacity int, rate float64, opts ...LocalOption) (DecisionLimiter, error) {
+	if capacity <= 0 || rate <= 0 {
+		return nil, ErrInvalidArgument
+	}
+	// 桶从空到满需要的时间，超过这个时间没有访问的 key 和新的 key 没有区别
+	idle := time.Duration(float64(capacity) / rate * float64(time.Second))
+	return &LocalTokenBucketLimiter{
+		store:    newLocalStore[tokenBucket](idle, opts),
+		capacity: capacity,
+		rate:     rate,
+	}, nil
+}
+
+func (l *LocalTokenBucketLimiter) Limit(ctx context.Context, key string) (bool, error) {
+	d, err := l.Allow(ctx, key)
+	return !d.Allowed, err
+}
+
+func (l *LocalTokenBucketLimiter) Allow(ctx context.Context, key string) (Decision, error) {
+	return l.store.allow(key, func(b *tokenBucket, now time.Time) Decision {
+		if b.ts.IsZero() {
+			// 新的 key，桶是满的
+			b.tokens = float64(l.capacity)
+		} else if elapsed := now.Sub(b.ts); elapsed > 0 {
+			b.tokens = math.Min(float64(l.capacity), b.tokens+elapsed.Seconds()*l.rate)
+		}
+		b.ts = now
+		d := Decision{Limit: int64(l.capacity)}
+		if b.tokens < 1 {
+			// 生成下一个令牌需要的时间
+			d.RetryAfter = l.duration(1 - b.tokens)
+		} else {
+			b.tokens--
+			d.Allowed = true
+		}
+		d.Remaining = int64(b.tokens)
+		d.ResetAfter = l.duration(float64(l.capacity) - b.tokens)
+		return d
+	}), nil
+}
+
+// duration 生成 tokens 个令牌需要的时间
+func (l *LocalTokenBucketLimiter) duration(tokens float64) time.Duration {
+	return time.Duration(math.Round(tokens / l.rate * float64(time.Second)))
+}
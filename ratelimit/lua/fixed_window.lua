@@ -0,0 +1,41 @@
+---
+---    Copyright 2023 wkRonin
+---
+---   Licensed under the Apache License, Version 2.0 (the "License");
+---    you may not use this file except in compliance with the License.
+---    You may obtain a copy of the License at
+---
+---        http://www.apache.org/licenses/LICENSE-2.0
+---
+---    Unless required by applicable law or agreed to in writing, software
+---    distributed under the License is distributed on an "AS IS" BASIS,
+---    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+---    See the License for the specific language governing permissions and
+---    limitations under the License.
+---
+
+-- 限流对象
+local key = KEYS[1]
+-- 窗口大小
+local window = tonumber(ARGV[1])
+-- 阈值
+local threshold = tonumber(ARGV[2])
+
+local cnt = redis.call('INCR', key)
+if cnt == 1 then
+    -- 窗口内的第一个请求，窗口从现在开始
+    redis.call('PEXPIRE', key, window)
+end
+local ttl = redis.call('PTTL', key)
+if ttl < 0 then
+    -- 没有过期时间的计数器永远不会重置，这里补上
+    redis.call('PEXPIRE', key, window)
+    ttl = window
+end
+-- 返回 {是否放行, 阈值, 剩余配额, 配额完全恢复的毫秒数, 可以重试的毫秒数}
+-- 返回数组时 false 会截断，所以用 1 和 0 表示是否放行
+if cnt > threshold then
+    return {0, threshold, 0, ttl, ttl}
+else
+    return {1, threshold, threshold - cnt, ttl, 0}
+end
@@ -0,0 +1,56 @@
+---
+---    Copyright 2023 wkRonin
+---
+---   Licensed under the Apache License, Version 2.0 (the "License");
+---    you may not use this file except in compliance with the License.
+---    You may obtain a copy of the License at
+---
+---        http://www.apache.org/licenses/LICENSE-2.0
+---
+---    Unless required by applicable law or agreed to in writing, software
+---    distributed under the License is distributed on an "AS IS" BASIS,
+---    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+---    See the License for the specific language governing permissions and
+---    limitations under the License.
+---
+
+-- 限流对象，hash 中保存桶里的水量 level 和上次更新的时间 ts
+local key = KEYS[1]
+-- 桶的容量，也就是最多可以排队的请求数
+local capacity = tonumber(ARGV[1])
+-- 每秒漏出的请求数
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call('HMGET', key, 'level', 'ts')
+local level = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if level == nil or ts == nil then
+    level = 0
+    ts = now
+end
+-- 按照流逝的时间漏水
+local elapsed = math.max(0, now - ts)
+level = math.max(0, level - elapsed * rate / 1000)
+-- 各个实例的时钟不一定一致，ts 只能往前走
+-- 否则时钟慢的实例把 ts 往回拨之后，时钟快的实例会把这段时间的水再漏一遍
+ts = math.max(ts, now)
+
+-- 排在当前请求前面的请求数
+local position = math.floor(level)
+local allowed = 0
+local retry_after = 0
+if level + 1 <= capacity then
+    allowed = 1
+    level = level + 1
+else
+    -- 漏出足够的水让当前请求进入桶需要的时间
+    retry_after = math.ceil((level + 1 - capacity) * 1000 / rate)
+end
+redis.call('HSET', key, 'level', level, 'ts', ts)
+-- 漏空之后和不存在没有区别，所以过期时间就是漏空需要的时间
+local reset_after = math.ceil(level * 1000 / rate)
+redis.call('PEXPIRE', key, reset_after + 1)
+-- 返回 {是否放行, 容量, 剩余可以排队的请求数, 漏空的毫秒数, 可以重试的毫秒数, 排在当前请求前面的请求数}
+-- 返回数组时 false 会截断，所以用 1 和 0 表示是否放行
+return {allowed, capacity, math.floor(capacity - level), reset_after, retry_after, position}
@@ -26,11 +26,22 @@ local min = now - window
 
 redis.call('ZREMRANGEBYSCORE', key, '-inf', min)
 local cnt = redis.call('ZCOUNT', key, '-inf', '+inf')
+-- 返回 {是否放行, 阈值, 剩余配额, 配额完全恢复的毫秒数, 可以重试的毫秒数}
+-- 返回数组时 false 会截断，所以用 1 和 0 表示是否放行
 if cnt >= threshold then
-    return "true"
+    -- 最早的请求滑出窗口之后才能重试
+    local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+    local newest = redis.call('ZRANGE', key, -1, -1, 'WITHSCORES')
+    local retry_after = 0
+    local reset_after = 0
+    if #oldest > 0 then
+        retry_after = tonumber(oldest[2]) + window - now
+        reset_after = tonumber(newest[2]) + window - now
+    end
+    return {0, threshold, 0, reset_after, retry_after}
 else
     -- 把 score 和 member 都设置成 now
     redis.call('ZADD', key, now, now)
     redis.call('PEXPIRE', key, window)
-    return "false"
-end
\ No newline at end of file
+    return {1, threshold, threshold - cnt - 1, window, 0}
+end
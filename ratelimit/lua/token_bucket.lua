@@ -0,0 +1,55 @@
+---
+---    Copyright 2023 wkRonin
+---
+---   Licensed under the Apache License, Version 2.0 (the "License");
+---    you may not use this file except in compliance with the License.
+---    You may obtain a copy of the License at
+---
+---        http://www.apache.org/licenses/LICENSE-2.0
+---
+---    Unless required by applicable law or agreed to in writing, software
+---    distributed under the License is distributed on an "AS IS" BASIS,
+---    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+---    See the License for the specific language governing permissions and
+---    limitations under the License.
+---
+
+-- 限流对象，hash 中保存剩余令牌数 tokens 和上次更新的时间 ts
+local key = KEYS[1]
+-- 桶的容量，也就是允许的最大突发流量
+local capacity = tonumber(ARGV[1])
+-- 每秒生成的令牌数
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local bucket = redis.call('HMGET', key, 'tokens', 'ts')
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+    -- 第一次访问或者已经过期，桶是满的
+    tokens = capacity
+    ts = now
+end
+-- 按照流逝的时间补充令牌
+local elapsed = math.max(0, now - ts)
+tokens = math.min(capacity, tokens + elapsed * rate / 1000)
+-- 各个实例的时钟不一定一致，ts 只能往前走
+-- 否则时钟慢的实例把 ts 往回拨之后，时钟快的实例会把这段时间的令牌再补充一遍
+ts = math.max(ts, now)
+
+local allowed = 0
+local retry_after = 0
+if tokens >= 1 then
+    allowed = 1
+    tokens = tokens - 1
+else
+    -- 生成下一个令牌需要的时间
+    retry_after = math.ceil((1 - tokens) * 1000 / rate)
+end
+redis.call('HSET', key, 'tokens', tokens, 'ts', ts)
+-- 桶填满之后和不存在没有区别，所以过期时间就是填满需要的时间
+local reset_after = math.ceil((capacity - tokens) * 1000 / rate)
+redis.call('PEXPIRE', key, reset_after + 1)
+-- 返回 {是否放行, 容量, 剩余令牌数, 桶填满的毫秒数, 可以重试的毫秒数}
+-- 返回数组时 false 会截断，所以用 1 和 0 表示是否放行
+return {allowed, capacity, math.floor(tokens), reset_after, retry_after}
@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 
+	ratelimit "github.com/wkRonin/toolkit/ratelimit"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -48,3 +49,56 @@ func (mr *MockLimiterMockRecorder) Limit(ctx, key interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limit", reflect.TypeOf((*MockLimiter)(nil).Limit), ctx, key)
 }
+
+// MockDecisionLimiter is a mock of DecisionLimiter interface.
+type MockDecisionLimiter struct {
+	ctrl     *gomock.Controller
+	recorder *MockDecisionLimiterMockRecorder
+}
+
+// MockDecisionLimiterMockRecorder is the mock recorder for MockDecisionLimiter.
+type MockDecisionLimiterMockRecorder struct {
+	mock *MockDecisionLimiter
+}
+
+// NewMockDecisionLimiter creates a new mock instance.
+func NewMockDecisionLimiter(ctrl *gomock.Controller) *MockDecisionLimiter {
+	mock := &MockDecisionLimiter{ctrl: ctrl}
+	mock.recorder = &MockDecisionLimiterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDecisionLimiter) EXPECT() *MockDecisionLimiterMockRecorder {
+	return m.recorder
+}
+
+// Allow mocks base method.
+func (m *MockDecisionLimiter) Allow(ctx context.Context, key string) (ratelimit.Decision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allow", ctx, key)
+	ret0, _ := ret[0].(ratelimit.Decision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allow indicates an expected call of Allow.
+func (mr *MockDecisionLimiterMockRecorder) Allow(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allow", reflect.TypeOf((*MockDecisionLimiter)(nil).Allow), ctx, key)
+}
+
+// Limit mocks base method.
+func (m *MockDecisionLimiter) Limit(ctx context.Context, key string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Limit", ctx, key)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Limit indicates an expected call of Limit.
+func (mr *MockDecisionLimiterMockRecorder) Limit(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Limit", reflect.TypeOf((*MockDecisionLimiter)(nil).Limit), ctx, key)
+}
@@ -0,0 +1,39 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"errors"
+	"time"
+)
+
+var errUnexpectedResult = errors.New("ratelimit: lua 脚本返回值错误")
+
+// decisionFromLua 解析 lua 脚本返回的 {allowed, limit, remaining, reset_after, retry_after}
+// allowed 用 1 和 0 表示，时间的单位都是毫秒
+func decisionFromLua(res []int64) (Decision, error) {
+	if len(res) < 5 {
+		return Decision{}, errUnexpectedResult
+	}
+	return Decision{
+		Allowed:    res[0] == 1,
+		Limit:      res[1],
+		Remaining:  res[2],
+		ResetAfter: time.Duration(res[3]) * time.Millisecond,
+		RetryAfter: time.Duration(res[4]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,56 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecisionFromLua(t *testing.T) {
+	testCases := []struct {
+		name    string
+		res     []int64
+		wantRes Decision
+		wantErr error
+	}{
+		{
+			name:    "放行",
+			res:     []int64{1, 100, 99, 1000, 0},
+			wantRes: Decision{Allowed: true, Limit: 100, Remaining: 99, ResetAfter: time.Second},
+		},
+		{
+			name:    "限流",
+			res:     []int64{0, 100, 0, 300, 200},
+			wantRes: Decision{Limit: 100, ResetAfter: 300 * time.Millisecond, RetryAfter: 200 * time.Millisecond},
+		},
+		{
+			name:    "返回值错误",
+			res:     []int64{1},
+			wantErr: errUnexpectedResult,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := decisionFromLua(tc.res)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantRes, res)
+		})
+	}
+}
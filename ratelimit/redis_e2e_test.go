@@ -0,0 +1,308 @@
+//go:build e2e
+
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type RedisLimiterE2ESuite struct {
+	suite.Suite
+	rdb redis.Cmdable
+}
+
+func (s *RedisLimiterE2ESuite) SetupSuite() {
+	s.rdb = redis.NewClient(&redis.Options{
+		Addr:     "localhost:6379",
+		Password: "", // no password set
+		DB:       0,  // use default DB
+	})
+	// 确保测试的目标 Redis 已经启动成功了
+	for s.rdb.Ping(context.Background()).Err() != nil {
+
+	}
+}
+
+func TestRedisLimiterE2E(t *testing.T) {
+	suite.Run(t, &RedisLimiterE2ESuite{})
+}
+
+// clock 手动推进的时钟，lua 脚本的计算都依赖传入的时间
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func (s *RedisLimiterE2ESuite) TestTokenBucket() {
+	t := s.T()
+	ctx := context.Background()
+	key := "ratelimit:e2e:token_bucket"
+	defer s.rdb.Del(ctx, key)
+
+	limiter, err := NewRedisTokenBucketLimiter(s.rdb, 2, 10)
+	require.NoError(t, err)
+	l := limiter.(*RedisTokenBucketLimiter)
+	c := &clock{t: time.Now()}
+	l.now = c.now
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		wantRes Decision
+	}{
+		{
+			name:    "桶满放行",
+			wantRes: Decision{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: 100 * time.Millisecond},
+		},
+		{
+			name:    "突发放行",
+			wantRes: Decision{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: 200 * time.Millisecond},
+		},
+		{
+			name: "令牌用完",
+			wantRes: Decision{Limit: 2, Remaining: 0,
+				ResetAfter: 200 * time.Millisecond, RetryAfter: 100 * time.Millisecond},
+		},
+		{
+			name:    "补充了半个令牌",
+			advance: 50 * time.Millisecond,
+			wantRes: Decision{Limit: 2, Remaining: 0,
+				ResetAfter: 150 * time.Millisecond, RetryAfter: 50 * time.Millisecond},
+		},
+		{
+			name:    "补充了一个令牌",
+			advance: 100 * time.Millisecond,
+			wantRes: Decision{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: 150 * time.Millisecond},
+		},
+		{
+			name:    "补充不超过容量",
+			advance: 10 * time.Second,
+			wantRes: Decision{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: 100 * time.Millisecond},
+		},
+	}
+	for _, step := range steps {
+		c.advance(step.advance)
+		res, err := l.Allow(ctx, key)
+		require.NoError(t, err, step.name)
+		assert.Equal(t, step.wantRes, res, step.name)
+	}
+}
+
+func (s *RedisLimiterE2ESuite) TestTokenBucket_ClockSkew() {
+	t := s.T()
+	ctx := context.Background()
+	key := "ratelimit:e2e:token_bucket_skew"
+	defer s.rdb.Del(ctx, key)
+
+	limiter, err := NewRedisTokenBucketLimiter(s.rdb, 2, 10)
+	require.NoError(t, err)
+	l := limiter.(*RedisTokenBucketLimiter)
+	// 两个实例的时钟相差 100ms，正好是生成一个令牌的时间
+	start := time.Now()
+	behind := func() time.Time { return start }
+	ahead := func() time.Time { return start.Add(100 * time.Millisecond) }
+
+	steps := []struct {
+		name        string
+		now         func() time.Time
+		wantAllowed bool
+	}{
+		{name: "时钟快的实例", now: ahead, wantAllowed: true},
+		{name: "时钟慢的实例", now: behind, wantAllowed: true},
+		// 时钟慢的实例不能把 ts 往回拨，否则这里会多补充一个令牌
+		{name: "时钟快的实例令牌用完", now: ahead},
+		{name: "时钟慢的实例令牌用完", now: behind},
+		{name: "交替调用不会补充令牌", now: ahead},
+	}
+	for _, step := range steps {
+		l.now = step.now
+		res, err := l.Allow(ctx, key)
+		require.NoError(t, err, step.name)
+		assert.Equal(t, step.wantAllowed, res.Allowed, step.name)
+	}
+}
+
+func (s *RedisLimiterE2ESuite) TestLeakyBucket() {
+	t := s.T()
+	ctx := context.Background()
+	key := "ratelimit:e2e:leaky_bucket"
+	defer s.rdb.Del(ctx, key)
+
+	l, err := NewRedisLeakyBucketLimiter(s.rdb, 2, 10)
+	require.NoError(t, err)
+	c := &clock{t: time.Now()}
+	l.now = c.now
+
+	steps := []struct {
+		name         string
+		advance      time.Duration
+		wantRes      Decision
+		wantPosition int64
+	}{
+		{
+			name:    "空桶放行",
+			wantRes: Decision{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: 100 * time.Millisecond},
+		},
+		{
+			name:         "排在第一个请求后面",
+			wantRes:      Decision{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: 200 * time.Millisecond},
+			wantPosition: 1,
+		},
+		{
+			name: "桶满限流",
+			wantRes: Decision{Limit: 2, Remaining: 0,
+				ResetAfter: 200 * time.Millisecond, RetryAfter: 100 * time.Millisecond},
+			wantPosition: 2,
+		},
+		{
+			name:         "漏出一个请求",
+			advance:      100 * time.Millisecond,
+			wantRes:      Decision{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: 200 * time.Millisecond},
+			wantPosition: 1,
+		},
+	}
+	for _, step := range steps {
+		c.advance(step.advance)
+		res, position, err := l.allow(ctx, key)
+		require.NoError(t, err, step.name)
+		assert.Equal(t, step.wantRes, res, step.name)
+		assert.Equal(t, step.wantPosition, position, step.name)
+	}
+}
+
+func (s *RedisLimiterE2ESuite) TestLeakyBucket_ClockSkew() {
+	t := s.T()
+	ctx := context.Background()
+	key := "ratelimit:e2e:leaky_bucket_skew"
+	defer s.rdb.Del(ctx, key)
+
+	l, err := NewRedisLeakyBucketLimiter(s.rdb, 2, 10)
+	require.NoError(t, err)
+	// 两个实例的时钟相差 100ms，正好是漏出一个请求的时间
+	start := time.Now()
+	behind := func() time.Time { return start }
+	ahead := func() time.Time { return start.Add(100 * time.Millisecond) }
+
+	steps := []struct {
+		name        string
+		now         func() time.Time
+		wantAllowed bool
+	}{
+		{name: "时钟快的实例", now: ahead, wantAllowed: true},
+		{name: "时钟慢的实例", now: behind, wantAllowed: true},
+		// 时钟慢的实例不能把 ts 往回拨，否则这里会多漏出一个请求
+		{name: "时钟快的实例桶满", now: ahead},
+		{name: "时钟慢的实例桶满", now: behind},
+		{name: "交替调用不会漏水", now: ahead},
+	}
+	for _, step := range steps {
+		l.now = step.now
+		res, err := l.Allow(ctx, key)
+		require.NoError(t, err, step.name)
+		assert.Equal(t, step.wantAllowed, res.Allowed, step.name)
+	}
+}
+
+func (s *RedisLimiterE2ESuite) TestSlidingWindow() {
+	t := s.T()
+	ctx := context.Background()
+	key := "ratelimit:e2e:sliding_window"
+	defer s.rdb.Del(ctx, key)
+
+	l := NewRedisSlidingWindowLimiter(s.rdb, time.Second, 2).(*RedisSlidingWindowLimiter)
+	c := &clock{t: time.Now()}
+	l.now = c.now
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		wantRes Decision
+	}{
+		{
+			name:    "窗口内第一个请求",
+			wantRes: Decision{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: time.Second},
+		},
+		{
+			name:    "窗口内第二个请求",
+			advance: 400 * time.Millisecond,
+			wantRes: Decision{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: time.Second},
+		},
+		{
+			name:    "超过阈值",
+			advance: 200 * time.Millisecond,
+			// 第一个请求还要 400ms 才滑出窗口，第二个请求还要 800ms
+			wantRes: Decision{Limit: 2, Remaining: 0,
+				ResetAfter: 800 * time.Millisecond, RetryAfter: 400 * time.Millisecond},
+		},
+		{
+			name:    "第一个请求刚好滑出窗口",
+			advance: 400 * time.Millisecond,
+			wantRes: Decision{Allowed: true, Limit: 2, Remaining: 0, ResetAfter: time.Second},
+		},
+	}
+	for _, step := range steps {
+		c.advance(step.advance)
+		res, err := l.Allow(ctx, key)
+		require.NoError(t, err, step.name)
+		assert.Equal(t, step.wantRes, res, step.name)
+	}
+}
+
+func (s *RedisLimiterE2ESuite) TestFixedWindow() {
+	t := s.T()
+	ctx := context.Background()
+	key := "ratelimit:e2e:fixed_window"
+	defer s.rdb.Del(ctx, key)
+
+	// 固定窗口依赖 redis 的过期时间，所以这里用真实的时间
+	l := NewRedisFixedWindowLimiter(s.rdb, 500*time.Millisecond, 2)
+	for i := int64(1); i >= 0; i-- {
+		res, err := l.Allow(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, i, res.Remaining)
+	}
+
+	res, err := l.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.True(t, res.RetryAfter > 0 && res.RetryAfter <= 500*time.Millisecond)
+	assert.Equal(t, res.ResetAfter, res.RetryAfter)
+
+	// 窗口结束之后计数重置
+	time.Sleep(res.RetryAfter + 10*time.Millisecond)
+	res, err = l.Allow(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, Decision{Allowed: true, Limit: 2, Remaining: 1, ResetAfter: res.ResetAfter}, res)
+	assert.True(t, res.ResetAfter > 0 && res.ResetAfter <= 500*time.Millisecond)
+}
@@ -0,0 +1,64 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	_ "embed"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/fixed_window.lua
+var luaFixedWindow string
+
+// RedisFixedWindowLimiter 固定窗口计数限流，每个 key 只占用一个计数器
+// 实现简单，但是相邻两个窗口的交界处最多可能放行两倍的请求
+type RedisFixedWindowLimiter struct {
+	cmd      redis.Cmdable
+	interval time.Duration
+	rate     int
+}
+
+func NewRedisFixedWindowLimiter(cmd redis.Cmdable, interval time.Duration, rate int) DecisionLimiter {
+	return &RedisFixedWindowLimiter{
+		cmd:      cmd,
+		interval: interval,
+		rate:     rate,
+	}
+}
+
+func (r *RedisFixedWindowLimiter) Limit(ctx context.Context, key string) (bool, error) {
+	d, err := r.Allow(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return !d.Allowed, nil
+}
+
+func (r *RedisFixedWindowLimiter) Allow(ctx context.Context, key string) (Decision, error) {
+	res, err := r.cmd.Eval(ctx,
+		luaFixedWindow,
+		[]string{key},
+		r.interval.Milliseconds(),
+		r.rate).Int64Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+	return decisionFromLua(res)
+}
@@ -0,0 +1,102 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	_ "embed"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/leaky_bucket.lua
+var luaLeakyBucket string
+
+var _ DecisionLimiter = &RedisLeakyBucketLimiter{}
+
+// RedisLeakyBucketLimiter 漏桶限流，请求按照 rate 的速率匀速流出
+// 桶里最多排队 capacity 个请求，超过的请求被限流
+type RedisLeakyBucketLimiter struct {
+	cmd      redis.Cmdable
+	capacity int
+	// rate 每秒漏出的请求数
+	rate float64
+	// now 当前时间，测试的时候可以替换
+	now func() time.Time
+}
+
+// NewRedisLeakyBucketLimiter 创建漏桶限流器，capacity 为桶的容量，rate 为每秒漏出的请求数
+// 返回具体的类型，这样可以直接调用 LimitWithPosition 和 Delay，同时它也实现了 DecisionLimiter
+// capacity 或者 rate 不大于 0 时返回 ErrInvalidArgument
+func NewRedisLeakyBucketLimiter(cmd redis.Cmdable, capacity int, rate float64) (*RedisLeakyBucketLimiter, error) {
+	if capacity <= 0 || rate <= 0 {
+		return nil, ErrInvalidArgument
+	}
+	return &RedisLeakyBucketLimiter{
+		cmd:      cmd,
+		capacity: capacity,
+		rate:     rate,
+		now:      time.Now,
+	}, nil
+}
+
+func (r *RedisLeakyBucketLimiter) Limit(ctx context.Context, key string) (bool, error) {
+	d, _, err := r.allow(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return !d.Allowed, nil
+}
+
+func (r *RedisLeakyBucketLimiter) Allow(ctx context.Context, key string) (Decision, error) {
+	d, _, err := r.allow(ctx, key)
+	return d, err
+}
+
+// LimitWithPosition 除了是否限流，还返回排在当前请求前面的请求数
+// 想要平滑流量的话，调用方可以在执行之前等待 Delay(position)
+func (r *RedisLeakyBucketLimiter) LimitWithPosition(ctx context.Context, key string) (limited bool, position int64, err error) {
+	d, position, err := r.allow(ctx, key)
+	if err != nil {
+		return false, 0, err
+	}
+	return !d.Allowed, position, nil
+}
+
+func (r *RedisLeakyBucketLimiter) allow(ctx context.Context, key string) (Decision, int64, error) {
+	res, err := r.cmd.Eval(ctx,
+		luaLeakyBucket,
+		[]string{key},
+		r.capacity,
+		r.rate,
+		r.now().UnixMilli()).Int64Slice()
+	if err != nil {
+		return Decision{}, 0, err
+	}
+	// 漏桶的脚本在通用的结果后面多返回了排队位置
+	if len(res) != 6 {
+		return Decision{}, 0, errUnexpectedResult
+	}
+	d, err := decisionFromLua(res)
+	return d, res[5], err
+}
+
+// Delay 排在 position 的请求需要等待的时间
+func (r *RedisLeakyBucketLimiter) Delay(position int64) time.Duration {
+	return time.Duration(float64(position) / r.rate * float64(time.Second))
+}
@@ -0,0 +1,39 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisLeakyBucketLimiter_Delay(t *testing.T) {
+	l, err := NewRedisLeakyBucketLimiter(nil, 10, 5)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), l.Delay(0))
+	assert.Equal(t, 600*time.Millisecond, l.Delay(3))
+}
+
+func TestNewRedisLeakyBucketLimiter(t *testing.T) {
+	_, err := NewRedisLeakyBucketLimiter(nil, 0, 5)
+	assert.Equal(t, ErrInvalidArgument, err)
+	_, err = NewRedisLeakyBucketLimiter(nil, 10, 0)
+	assert.Equal(t, ErrInvalidArgument, err)
+}
@@ -31,21 +31,36 @@ type RedisSlidingWindowLimiter struct {
 	cmd      redis.Cmdable
 	interval time.Duration
 	rate     int
+	// now 当前时间，测试的时候可以替换
+	now func() time.Time
 }
 
-func NewRedisSlidingWindowLimiter(cmd redis.Cmdable, interval time.Duration, rate int) Limiter {
+func NewRedisSlidingWindowLimiter(cmd redis.Cmdable, interval time.Duration, rate int) DecisionLimiter {
 	return &RedisSlidingWindowLimiter{
 		cmd:      cmd,
 		interval: interval,
 		rate:     rate,
+		now:      time.Now,
 	}
 }
 
 func (r *RedisSlidingWindowLimiter) Limit(ctx context.Context, key string) (bool, error) {
-	return r.cmd.Eval(ctx,
+	d, err := r.Allow(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return !d.Allowed, nil
+}
+
+func (r *RedisSlidingWindowLimiter) Allow(ctx context.Context, key string) (Decision, error) {
+	res, err := r.cmd.Eval(ctx,
 		luaScript,
 		[]string{key},
 		r.interval.Milliseconds(),
 		r.rate,
-		time.Now().UnixMilli()).Bool()
+		r.now().UnixMilli()).Int64Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+	return decisionFromLua(res)
 }
@@ -0,0 +1,74 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"context"
+	_ "embed"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+//go:embed lua/token_bucket.lua
+var luaTokenBucket string
+
+// RedisTokenBucketLimiter 令牌桶限流，每个 key 只占用一个 hash，内存占用和请求量无关
+// 桶满的时候允许 capacity 个请求的突发流量，之后按照 rate 的速率放行
+type RedisTokenBucketLimiter struct {
+	cmd      redis.Cmdable
+	capacity int
+	// rate 每秒生成的令牌数
+	rate float64
+	// now 当前时间，测试的时候可以替换
+	now func() time.Time
+}
+
+// NewRedisTokenBucketLimiter 创建令牌桶限流器，capacity 为桶的容量，rate 为每秒生成的令牌数
+// capacity 或者 rate 不大于 0 时返回 ErrInvalidArgument，否则 lua 脚本中会除以 0
+func NewRedisTokenBucketLimiter(cmd redis.Cmdable, capacity int, rate float64) (DecisionLimiter, error) {
+	if capacity <= 0 || rate <= 0 {
+		return nil, ErrInvalidArgument
+	}
+	return &RedisTokenBucketLimiter{
+		cmd:      cmd,
+		capacity: capacity,
+		rate:     rate,
+		now:      time.Now,
+	}, nil
+}
+
+func (r *RedisTokenBucketLimiter) Limit(ctx context.Context, key string) (bool, error) {
+	d, err := r.Allow(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return !d.Allowed, nil
+}
+
+func (r *RedisTokenBucketLimiter) Allow(ctx context.Context, key string) (Decision, error) {
+	res, err := r.cmd.Eval(ctx,
+		luaTokenBucket,
+		[]string{key},
+		r.capacity,
+		r.rate,
+		r.now().UnixMilli()).Int64Slice()
+	if err != nil {
+		return Decision{}, err
+	}
+	return decisionFromLua(res)
+}
@@ -0,0 +1,32 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRedisTokenBucketLimiter(t *testing.T) {
+	_, err := NewRedisTokenBucketLimiter(nil, 0, 10)
+	assert.Equal(t, ErrInvalidArgument, err)
+	_, err = NewRedisTokenBucketLimiter(nil, 10, 0)
+	assert.Equal(t, ErrInvalidArgument, err)
+	_, err = NewRedisTokenBucketLimiter(nil, 10, -1)
+	assert.Equal(t, ErrInvalidArgument, err)
+}
@@ -16,9 +16,37 @@
 
 package ratelimit
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrInvalidArgument 创建限流器时传入的容量、速率或者窗口大小不合法
+var ErrInvalidArgument = errors.New("ratelimit: 容量、速率和窗口大小必须大于 0")
 
 //go:generate mockgen -source=./types.go -package=limitmocks -destination=mocks/limiter.mock.go Limiter
 type Limiter interface {
 	Limit(ctx context.Context, key string) (bool, error)
 }
+
+// DecisionLimiter 除了是否限流，还能返回剩余配额等详细信息，方便客户端退避
+// 本包所有的限流器都实现了这个接口
+type DecisionLimiter interface {
+	Limiter
+	Allow(ctx context.Context, key string) (Decision, error)
+}
+
+// Decision 一次限流判断的详细结果
+type Decision struct {
+	// Allowed 是否放行，和 Limit 的返回值相反
+	Allowed bool
+	// Limit 窗口内或者桶的配额
+	Limit int64
+	// Remaining 放行当前请求之后剩余的配额
+	Remaining int64
+	// ResetAfter 配额完全恢复需要的时间
+	ResetAfter time.Duration
+	// RetryAfter 被限流时最早可以重试的时间，放行时为 0
+	RetryAfter time.Duration
+}
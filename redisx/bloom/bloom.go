@@ -0,0 +1,114 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package bloom
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/wkRonin/toolkit/containerx/bloom"
+)
+
+var (
+	//go:embed lua/add.lua
+	luaAdd string
+	//go:embed lua/contains.lua
+	luaContains string
+)
+
+// maxBits Redis 中 bitmap 最多的位数，偏移量的上限是 2^32-1
+const maxBits = 1 << 32
+
+var ErrTooManyBits = errors.New("bloom: 需要的位数超过了 Redis bitmap 的上限 2^32")
+
+var _ bloom.BloomFilter = &RedisBloomFilter{}
+
+// RedisBloomFilter 基于 Redis bitmap 的布隆过滤器，多个实例可以共享
+// 使用 lua 脚本保证一次操作的多个位是原子的
+// 注意 Redis 的 bitmap 最多 2^32 位，也就是 512MB
+type RedisBloomFilter struct {
+	cmd redis.Cmdable
+	key string
+	m   uint64
+	k   uint64
+}
+
+// NewRedisBloomFilter 根据预期的元素数量和误判率创建布隆过滤器，key 为 Redis 中 bitmap 的 key
+// 共享同一个 key 的实例必须使用相同的参数
+// 计算出来的位数超过 2^32 时返回 ErrTooManyBits，这时需要调小预期的元素数量或者调大误判率，或者按 key 拆分成多个过滤器
+func NewRedisBloomFilter(cmd redis.Cmdable, key string, expected uint64, falsePositiveRate float64) (*RedisBloomFilter, error) {
+	m, k := bloom.OptimalParams(expected, falsePositiveRate)
+	if m > maxBits {
+		return nil, ErrTooManyBits
+	}
+	return &RedisBloomFilter{
+		cmd: cmd,
+		key: key,
+		m:   m,
+		k:   k,
+	}, nil
+}
+
+func (r *RedisBloomFilter) Add(ctx context.Context, key string) error {
+	return r.AddBatch(ctx, []string{key})
+}
+
+func (r *RedisBloomFilter) AddBatch(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	args := make([]any, 0, uint64(len(keys))*r.k)
+	for _, key := range keys {
+		for _, loc := range bloom.Locations(key, r.k, r.m) {
+			args = append(args, loc)
+		}
+	}
+	return r.cmd.Eval(ctx, luaAdd, []string{r.key}, args...).Err()
+}
+
+func (r *RedisBloomFilter) MightContain(ctx context.Context, key string) (bool, error) {
+	res, err := r.MightContainBatch(ctx, []string{key})
+	if err != nil {
+		return false, err
+	}
+	return res[0], nil
+}
+
+func (r *RedisBloomFilter) MightContainBatch(ctx context.Context, keys []string) ([]bool, error) {
+	if len(keys) == 0 {
+		return []bool{}, nil
+	}
+	args := make([]any, 0, 1+uint64(len(keys))*r.k)
+	args = append(args, r.k)
+	for _, key := range keys {
+		for _, loc := range bloom.Locations(key, r.k, r.m) {
+			args = append(args, loc)
+		}
+	}
+	vals, err := r.cmd.Eval(ctx, luaContains, []string{r.key}, args...).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]bool, len(vals))
+	for i, val := range vals {
+		res[i] = val == 1
+	}
+	return res, nil
+}
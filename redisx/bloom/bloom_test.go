@@ -0,0 +1,140 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package bloom
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	redismocks "github.com/wkRonin/toolkit/redisx/lock/mocks"
+)
+
+func TestRedisBloomFilter_AddBatch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) redis.Cmdable
+		keys    []string
+		wantErr error
+	}{
+		{
+			name: "成功",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmdable := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetVal(int64(14))
+				cmdable.EXPECT().Eval(gomock.Any(), luaAdd, []string{"bf"}, gomock.Any()).
+					Return(res)
+				return cmdable
+			},
+			keys: []string{"1", "2"},
+		},
+		{
+			name: "redis 错误",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmdable := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetErr(errors.New("network error"))
+				cmdable.EXPECT().Eval(gomock.Any(), luaAdd, []string{"bf"}, gomock.Any()).
+					Return(res)
+				return cmdable
+			},
+			keys:    []string{"1"},
+			wantErr: errors.New("network error"),
+		},
+		{
+			name: "空切片不访问 redis",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				return redismocks.NewMockCmdable(ctrl)
+			},
+			keys: []string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			b, err := NewRedisBloomFilter(tc.mock(ctrl), "bf", 1000, 0.01)
+			require.NoError(t, err)
+			err = b.AddBatch(context.Background(), tc.keys)
+			assert.Equal(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestRedisBloomFilter_MightContainBatch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		mock    func(ctrl *gomock.Controller) redis.Cmdable
+		keys    []string
+		want    []bool
+		wantErr error
+	}{
+		{
+			name: "成功",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmdable := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetVal([]any{int64(1), int64(0)})
+				cmdable.EXPECT().Eval(gomock.Any(), luaContains, []string{"bf"}, gomock.Any()).
+					Return(res)
+				return cmdable
+			},
+			keys: []string{"1", "2"},
+			want: []bool{true, false},
+		},
+		{
+			name: "redis 错误",
+			mock: func(ctrl *gomock.Controller) redis.Cmdable {
+				cmdable := redismocks.NewMockCmdable(ctrl)
+				res := redis.NewCmd(context.Background())
+				res.SetErr(errors.New("network error"))
+				cmdable.EXPECT().Eval(gomock.Any(), luaContains, []string{"bf"}, gomock.Any()).
+					Return(res)
+				return cmdable
+			},
+			keys:    []string{"1"},
+			wantErr: errors.New("network error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			b, err := NewRedisBloomFilter(tc.mock(ctrl), "bf", 1000, 0.01)
+			require.NoError(t, err)
+			res, err := b.MightContainBatch(context.Background(), tc.keys)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.want, res)
+		})
+	}
+}
+
+func TestNewRedisBloomFilter(t *testing.T) {
+	// 10 亿个元素、1% 的误判率大约需要 96 亿位，超过了 bitmap 的上限
+	_, err := NewRedisBloomFilter(nil, "bf", 1_000_000_000, 0.01)
+	assert.Equal(t, ErrTooManyBits, err)
+
+	b, err := NewRedisBloomFilter(nil, "bf", 100_000_000, 0.01)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, b.m, uint64(maxBits))
+}
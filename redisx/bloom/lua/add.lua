@@ -0,0 +1,23 @@
+---
+---    Copyright 2023 wkRonin
+---
+---   Licensed under the Apache License, Version 2.0 (the "License");
+---    you may not use this file except in compliance with the License.
+---    You may obtain a copy of the License at
+---
+---        http://www.apache.org/licenses/LICENSE-2.0
+---
+---    Unless required by applicable law or agreed to in writing, software
+---    distributed under the License is distributed on an "AS IS" BASIS,
+---    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+---    See the License for the specific language governing permissions and
+---    limitations under the License.
+---
+
+-- 布隆过滤器对应的位数组
+local key = KEYS[1]
+-- ARGV 中是所有需要置为1的位置
+for i = 1, #ARGV do
+    redis.call('SETBIT', key, ARGV[i], 1)
+end
+return #ARGV
@@ -0,0 +1,34 @@
+---
+---    Copyright 2023 wkRonin
+---
+---   Licensed under the Apache License, Version 2.0 (the "License");
+---    you may not use this file except in compliance with the License.
+---    You may obtain a copy of the License at
+---
+---        http://www.apache.org/licenses/LICENSE-2.0
+---
+---    Unless required by applicable law or agreed to in writing, software
+---    distributed under the License is distributed on an "AS IS" BASIS,
+---    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+---    See the License for the specific language governing permissions and
+---    limitations under the License.
+---
+
+-- 布隆过滤器对应的位数组
+local key = KEYS[1]
+-- 每个元素对应的位置数量，也就是哈希函数的个数
+local k = tonumber(ARGV[1])
+-- 之后每 k 个位置对应一个元素
+local n = (#ARGV - 1) / k
+local res = {}
+for i = 0, n - 1 do
+    local exist = 1
+    for j = 1, k do
+        if redis.call('GETBIT', key, ARGV[1 + i * k + j]) == 0 then
+            exist = 0
+            break
+        end
+    end
+    res[i + 1] = exist
+end
+return res
@@ -27,7 +27,8 @@ import (
 	"github.com/redis/go-redis/v9"
 
 	"github.com/google/uuid"
-	"golang.org/x/sync/singleflight"
+
+	"github.com/wkRonin/toolkit/syncx"
 )
 
 var (
@@ -48,7 +49,7 @@ var (
 
 type Client struct {
 	client redis.Cmdable
-	g      singleflight.Group
+	g      syncx.SingleFlight[string, *Lock]
 	// valuer 用于生成值
 	valuer func() string
 }
@@ -65,7 +66,7 @@ func NewClient(client redis.Cmdable) *Client {
 func (c *Client) SingleflightLock(ctx context.Context, key string, expiration time.Duration, retry RetryStrategy, timeout time.Duration) (*Lock, error) {
 	for {
 		flag := false
-		result := c.g.DoChan(key, func() (interface{}, error) {
+		result := c.g.DoChan(key, func() (*Lock, error) {
 			flag = true
 			return c.Lock(ctx, key, expiration, retry, timeout)
 		})
@@ -76,7 +77,7 @@ func (c *Client) SingleflightLock(ctx context.Context, key string, expiration ti
 				if res.Err != nil {
 					return nil, res.Err
 				}
-				return res.Val.(*Lock), nil
+				return res.Val, nil
 			}
 		case <-ctx.Done():
 			return nil, ctx.Err()
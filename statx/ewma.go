@@ -0,0 +1,92 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package statx
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// EWMA 指数加权移动平均，并发安全
+// 固定 alpha 时每次 Update 新值的权重都是 alpha
+// 按时间衰减时新值的权重由距离上一次 Update 的时间决定，适合采样不均匀的场景，例如 p2c 负载均衡统计延迟
+type EWMA struct {
+	mutex sync.Mutex
+	alpha float64
+	// tau > 0 时按时间衰减，经过 tau 之后旧值的权重衰减到 1/e
+	tau   time.Duration
+	value float64
+	last  time.Time
+	// 第一次 Update 直接使用新值，避免从 0 开始爬升
+	initialized bool
+	now         func() time.Time
+}
+
+// NewEWMA 创建固定 alpha 的 EWMA，alpha 取值 (0, 1]，越大越看重新值
+func NewEWMA(alpha float64) *EWMA {
+	return &EWMA{
+		alpha: alpha,
+		now:   time.Now,
+	}
+}
+
+// NewDecayEWMA 创建按时间衰减的 EWMA
+func NewDecayEWMA(tau time.Duration) *EWMA {
+	return &EWMA{
+		tau: tau,
+		now: time.Now,
+	}
+}
+
+// Update 加入一个新的采样值
+func (e *EWMA) Update(v float64) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	now := e.now()
+	if !e.initialized {
+		e.value = v
+		e.last = now
+		e.initialized = true
+		return
+	}
+	alpha := e.alpha
+	if e.tau > 0 {
+		elapsed := now.Sub(e.last)
+		if elapsed < 0 {
+			elapsed = 0
+		}
+		alpha = 1 - math.Exp(-float64(elapsed)/float64(e.tau))
+	}
+	e.value = alpha*v + (1-alpha)*e.value
+	e.last = now
+}
+
+// Value 当前的平均值，没有采样时返回 0
+func (e *EWMA) Value() float64 {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.value
+}
+
+// Reset 清空所有采样
+func (e *EWMA) Reset() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.value = 0
+	e.initialized = false
+}
@@ -0,0 +1,60 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package statx
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEWMA(t *testing.T) {
+	e := NewEWMA(0.5)
+	assert.Equal(t, float64(0), e.Value())
+	e.Update(10)
+	assert.Equal(t, float64(10), e.Value())
+	e.Update(20)
+	assert.Equal(t, float64(15), e.Value())
+	e.Update(5)
+	assert.Equal(t, float64(10), e.Value())
+	e.Reset()
+	e.Update(4)
+	assert.Equal(t, float64(4), e.Value())
+}
+
+func TestEWMA_Decay(t *testing.T) {
+	now := time.Unix(1000, 0)
+	e := NewDecayEWMA(time.Second)
+	e.now = func() time.Time { return now }
+	e.Update(10)
+
+	// 同一时刻的采样不影响平均值
+	e.Update(100)
+	assert.Equal(t, float64(10), e.Value())
+
+	// 经过 tau 之后旧值的权重为 1/e
+	now = now.Add(time.Second)
+	e.Update(20)
+	assert.InDelta(t, 20-10/math.E, e.Value(), 1e-9)
+
+	// 很久之后基本只剩新值
+	now = now.Add(time.Minute)
+	e.Update(1)
+	assert.InDelta(t, float64(1), e.Value(), 1e-9)
+}
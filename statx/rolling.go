@@ -0,0 +1,139 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package statx
+
+import (
+	"sync"
+	"time"
+)
+
+// RollingStats 滚动窗口内的统计结果
+type RollingStats struct {
+	Success int64
+	Failure int64
+	// Latency 窗口内所有请求的耗时之和
+	Latency time.Duration
+}
+
+func (s RollingStats) Total() int64 {
+	return s.Success + s.Failure
+}
+
+// FailureRate 失败率，没有请求时返回 0
+func (s RollingStats) FailureRate() float64 {
+	total := s.Total()
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Failure) / float64(total)
+}
+
+// AvgLatency 平均耗时，没有请求时返回 0
+func (s RollingStats) AvgLatency() time.Duration {
+	total := s.Total()
+	if total == 0 {
+		return 0
+	}
+	return s.Latency / time.Duration(total)
+}
+
+type rollingBucket struct {
+	// 桶对应的时间片序号，和当前序号相差超过桶数时说明桶已经过期
+	index int64
+	RollingStats
+}
+
+// RollingCounter 按时间分桶的滚动计数器，并发安全
+// 窗口被均分成若干个桶，过期的桶在下一次访问时被复用，统计结果的精度为一个桶的时长
+// 适合熔断、自适应限流等需要最近一段时间成功率和耗时的场景
+type RollingCounter struct {
+	mutex          sync.Mutex
+	buckets        []rollingBucket
+	bucketDuration time.Duration
+	now            func() time.Time
+}
+
+// NewRollingCounter 创建窗口为 window，分成 buckets 个桶的滚动计数器
+func NewRollingCounter(window time.Duration, buckets int) *RollingCounter {
+	if buckets < 1 {
+		buckets = 1
+	}
+	bucketDuration := window / time.Duration(buckets)
+	if bucketDuration <= 0 {
+		bucketDuration = 1
+	}
+	return &RollingCounter{
+		buckets:        make([]rollingBucket, buckets),
+		bucketDuration: bucketDuration,
+		now:            time.Now,
+	}
+}
+
+// Success 记录一次成功的请求
+func (r *RollingCounter) Success(latency time.Duration) {
+	r.add(true, latency)
+}
+
+// Failure 记录一次失败的请求
+func (r *RollingCounter) Failure(latency time.Duration) {
+	r.add(false, latency)
+}
+
+func (r *RollingCounter) add(success bool, latency time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	idx := r.now().UnixNano() / int64(r.bucketDuration)
+	b := &r.buckets[idx%int64(len(r.buckets))]
+	if b.index != idx {
+		// 桶里是上一轮的数据，清空后复用
+		b.index = idx
+		b.RollingStats = RollingStats{}
+	}
+	if success {
+		b.Success++
+	} else {
+		b.Failure++
+	}
+	b.Latency += latency
+}
+
+// Stats 汇总窗口内所有未过期的桶
+func (r *RollingCounter) Stats() RollingStats {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	idx := r.now().UnixNano() / int64(r.bucketDuration)
+	n := int64(len(r.buckets))
+	var res RollingStats
+	for _, b := range r.buckets {
+		if idx-b.index >= n || b.index > idx {
+			continue
+		}
+		res.Success += b.Success
+		res.Failure += b.Failure
+		res.Latency += b.Latency
+	}
+	return res
+}
+
+// Reset 清空所有的桶
+func (r *RollingCounter) Reset() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for i := range r.buckets {
+		r.buckets[i] = rollingBucket{}
+	}
+}
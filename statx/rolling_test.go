@@ -0,0 +1,70 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package statx
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollingCounter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	r := NewRollingCounter(time.Second, 10)
+	r.now = func() time.Time { return now }
+
+	r.Success(10 * time.Millisecond)
+	r.Failure(30 * time.Millisecond)
+	now = now.Add(500 * time.Millisecond)
+	r.Success(20 * time.Millisecond)
+	stats := r.Stats()
+	assert.Equal(t, RollingStats{Success: 2, Failure: 1, Latency: 60 * time.Millisecond}, stats)
+	assert.Equal(t, int64(3), stats.Total())
+	assert.InDelta(t, 1.0/3, stats.FailureRate(), 1e-9)
+	assert.Equal(t, 20*time.Millisecond, stats.AvgLatency())
+
+	// 第一个桶滑出窗口
+	now = now.Add(500 * time.Millisecond)
+	assert.Equal(t, RollingStats{Success: 1, Latency: 20 * time.Millisecond}, r.Stats())
+
+	// 复用过期的桶
+	r.Failure(time.Millisecond)
+	assert.Equal(t, RollingStats{Success: 1, Failure: 1, Latency: 21 * time.Millisecond}, r.Stats())
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, RollingStats{}, r.Stats())
+	assert.Equal(t, float64(0), r.Stats().FailureRate())
+	assert.Equal(t, time.Duration(0), r.Stats().AvgLatency())
+}
+
+func TestRollingCounter_Concurrent(t *testing.T) {
+	r := NewRollingCounter(time.Minute, 6)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				r.Success(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(10000), r.Stats().Success)
+}
@@ -0,0 +1,180 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package statx
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// TDigest 估算分位数的 t-digest，并发安全
+// 用有限个质心近似数据分布，越靠近两端的质心越小，所以 P99 这类尾部分位数依然准确
+// 多个 TDigest 可以合并，适合每个实例各自统计之后再汇总
+type TDigest struct {
+	mutex       sync.Mutex
+	compression float64
+	// centroids 已经压缩过的质心，按 mean 升序
+	centroids []centroid
+	// buffer 还没有压缩的采样，攒够了再统一压缩，均摊排序的开销
+	buffer []centroid
+	total  float64
+	min    float64
+	max    float64
+}
+
+// NewTDigest 创建 t-digest，compression 越大越精确，占用的内存也越多，一般取 100
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = 100
+	}
+	return &TDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add 加入一个采样值
+func (t *TDigest) Add(x float64) {
+	t.AddWeighted(x, 1)
+}
+
+// AddWeighted 加入一个带权重的采样值，NaN 和非正的权重会被忽略
+func (t *TDigest) AddWeighted(x float64, weight float64) {
+	if math.IsNaN(x) || weight <= 0 {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.add(centroid{mean: x, weight: weight})
+}
+
+func (t *TDigest) add(c centroid) {
+	t.buffer = append(t.buffer, c)
+	t.total += c.weight
+	if c.mean < t.min {
+		t.min = c.mean
+	}
+	if c.mean > t.max {
+		t.max = c.mean
+	}
+	if len(t.buffer) >= int(t.compression)*5 {
+		t.compress()
+	}
+}
+
+// Count 所有采样的权重之和
+func (t *TDigest) Count() float64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.total
+}
+
+// Merge 把 other 的数据合并进来，other 不会被修改
+func (t *TDigest) Merge(other *TDigest) {
+	if other == t {
+		return
+	}
+	// 先复制 other 的数据再加锁，避免两个 TDigest 互相合并时死锁
+	other.mutex.Lock()
+	other.compress()
+	cs := make([]centroid, len(other.centroids))
+	copy(cs, other.centroids)
+	// 压缩之后的质心只保留了均值，真实的最小值和最大值需要单独合并
+	otherMin, otherMax := other.min, other.max
+	other.mutex.Unlock()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for _, c := range cs {
+		t.add(c)
+	}
+	t.min = math.Min(t.min, otherMin)
+	t.max = math.Max(t.max, otherMax)
+}
+
+// Quantile 估算分位数 q，q 取值 [0, 1]，没有数据时返回 NaN
+func (t *TDigest) Quantile(q float64) float64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.compress()
+	if len(t.centroids) == 0 || q < 0 || q > 1 {
+		return math.NaN()
+	}
+	if q == 0 {
+		return t.min
+	}
+	if q == 1 {
+		return t.max
+	}
+	target := q * t.total
+	first := t.centroids[0]
+	if target < first.weight/2 {
+		// 在最小值和第一个质心之间线性插值
+		return t.min + (first.mean-t.min)*target/(first.weight/2)
+	}
+	cum := 0.0
+	for i := 0; i < len(t.centroids)-1; i++ {
+		cur, next := t.centroids[i], t.centroids[i+1]
+		left := cum + cur.weight/2
+		right := cum + cur.weight + next.weight/2
+		if target < right {
+			return cur.mean + (next.mean-cur.mean)*(target-left)/(right-left)
+		}
+		cum += cur.weight
+	}
+	last := t.centroids[len(t.centroids)-1]
+	left := t.total - last.weight/2
+	return last.mean + (t.max-last.mean)*(target-left)/(last.weight/2)
+}
+
+// compress 把 buffer 和已有的质心按 mean 排序之后重新合并
+// 相邻的质心合并之后的权重不能超过 4 * total * q * (1 - q) / compression
+func (t *TDigest) compress() {
+	if len(t.buffer) == 0 {
+		return
+	}
+	all := append(t.centroids, t.buffer...)
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].mean < all[j].mean
+	})
+	res := make([]centroid, 0, len(t.centroids)+1)
+	cur := all[0]
+	cum := 0.0
+	for _, c := range all[1:] {
+		q := (cum + (cur.weight+c.weight)/2) / t.total
+		limit := 4 * t.total * q * (1 - q) / t.compression
+		if cur.weight+c.weight <= limit {
+			w := cur.weight + c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / w
+			cur.weight = w
+			continue
+		}
+		cum += cur.weight
+		res = append(res, cur)
+		cur = c
+	}
+	res = append(res, cur)
+	t.centroids = res
+	t.buffer = t.buffer[:0]
+}
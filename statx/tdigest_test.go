@@ -0,0 +1,117 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package statx
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTDigest_Quantile(t *testing.T) {
+	testCases := []struct {
+		name string
+		data func() []float64
+	}{
+		{
+			name: "均匀分布",
+			data: func() []float64 {
+				res := make([]float64, 100000)
+				for i := range res {
+					res[i] = rand.Float64() * 1000
+				}
+				return res
+			},
+		},
+		{
+			name: "指数分布",
+			data: func() []float64 {
+				res := make([]float64, 100000)
+				for i := range res {
+					res[i] = rand.ExpFloat64() * 100
+				}
+				return res
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := tc.data()
+			td := NewTDigest(100)
+			for _, v := range data {
+				td.Add(v)
+			}
+			sort.Float64s(data)
+			assert.Equal(t, float64(len(data)), td.Count())
+			assert.Equal(t, data[0], td.Quantile(0))
+			assert.Equal(t, data[len(data)-1], td.Quantile(1))
+			for _, q := range []float64{0.01, 0.1, 0.5, 0.9, 0.99, 0.999} {
+				// 估算值对应的真实分位数和 q 的误差
+				got := td.Quantile(q)
+				rank := float64(sort.SearchFloat64s(data, got)) / float64(len(data))
+				assert.InDelta(t, q, rank, 0.005, "q = %v", q)
+			}
+		})
+	}
+}
+
+func TestTDigest_Merge(t *testing.T) {
+	a, b := NewTDigest(100), NewTDigest(100)
+	for i := 0; i < 50000; i++ {
+		a.Add(float64(i))
+		b.Add(float64(i + 50000))
+	}
+	a.Merge(b)
+	assert.Equal(t, float64(100000), a.Count())
+	assert.Equal(t, float64(50000), b.Count())
+	assert.InDelta(t, 50000, a.Quantile(0.5), 500)
+	assert.InDelta(t, 99000, a.Quantile(0.99), 100)
+	assert.Equal(t, float64(0), a.Quantile(0))
+	assert.Equal(t, float64(99999), a.Quantile(1))
+}
+
+func TestTDigest_MergeMinMax(t *testing.T) {
+	// 压缩比例很小的时候，两端的质心会包含多个数据，均值不再是最小值和最大值
+	a, b := NewTDigest(100), NewTDigest(1)
+	for i := 0; i < 10000; i++ {
+		b.Add(float64(i))
+	}
+	a.Merge(b)
+	assert.Equal(t, float64(0), a.Quantile(0))
+	assert.Equal(t, float64(9999), a.Quantile(1))
+
+	// 合并之后保留双方中更小的最小值和更大的最大值
+	c := NewTDigest(1)
+	c.Add(-1)
+	c.Merge(b)
+	assert.Equal(t, float64(-1), c.Quantile(0))
+	assert.Equal(t, float64(9999), c.Quantile(1))
+}
+
+func TestTDigest_Empty(t *testing.T) {
+	td := NewTDigest(0)
+	assert.True(t, math.IsNaN(td.Quantile(0.5)))
+	td.Add(math.NaN())
+	td.AddWeighted(1, 0)
+	assert.Equal(t, float64(0), td.Count())
+	td.Add(3)
+	assert.Equal(t, float64(3), td.Quantile(0.5))
+	assert.True(t, math.IsNaN(td.Quantile(1.5)))
+}
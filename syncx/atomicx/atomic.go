@@ -16,46 +16,193 @@
 
 package atomicx
 
-import "sync/atomic"
+import (
+	"reflect"
+	"sync/atomic"
+)
 
-// Value 是对 atomic.Value 的泛型封装
-// 使用 NewValue 或者 NewValueOf 来创建实例
+// Value 是基于 atomic.Pointer 的泛型原子值
+// 读取时不需要类型断言，比原生 atomic.Value 快
+// 每次写入都会分配一个新的 T，Store 和 Swap 比原生 atomic.Value 慢，T 本身是指针时更推荐直接使用 atomic.Pointer
+// 零值可以直接使用，Load 返回 T 的零值
 type Value[T any] struct {
-	val atomic.Value
+	p atomic.Pointer[T]
+	// eq 是 CompareAndSwap 使用的比较函数，每个 T 只需要根据类型生成一次
+	// 使用 NewValue 和 NewValueOf 创建时直接生成，零值在第一次 CompareAndSwap 时生成
+	eq atomic.Pointer[func(a, b T) bool]
 }
 
 // NewValue 会创建一个 Value 对象，里面存放着 T 的零值
-// 注意，这个零值是带了类型的零值
 func NewValue[T any]() *Value[T] {
-	var t T
-	return NewValueOf[T](t)
+	v := &Value[T]{}
+	v.eq.Store(newEqual[T]())
+	return v
 }
 
 // NewValueOf 会使用传入的值来创建一个 Value 对象
 func NewValueOf[T any](t T) *Value[T] {
-	val := atomic.Value{}
-	val.Store(t)
-	return &Value[T]{
-		val: val,
-	}
+	v := NewValue[T]()
+	v.p.Store(&t)
+	return v
 }
 
 func (v *Value[T]) Load() (val T) {
-	data := v.val.Load()
-	val = data.(T)
+	if p := v.p.Load(); p != nil {
+		val = *p
+	}
 	return
 }
 
 func (v *Value[T]) Store(val T) {
-	v.val.Store(val)
+	v.p.Store(&val)
 }
 
 func (v *Value[T]) Swap(new T) (old T) {
-	data := v.val.Swap(new)
-	old = data.(T)
+	if p := v.p.Swap(&new); p != nil {
+		old = *p
+	}
 	return
 }
 
+// CompareAndSwap 当前值等于 old 时替换成 new
+// 和 atomic.Value 不同，T 或者它的动态类型不可比较时直接返回 false，不会 panic
 func (v *Value[T]) CompareAndSwap(old, new T) (swapped bool) {
-	return v.val.CompareAndSwap(old, new)
+	eq := v.equal()
+	for {
+		p := v.p.Load()
+		var cur T
+		if p != nil {
+			cur = *p
+		}
+		if !eq(cur, old) {
+			return false
+		}
+		// 比较成功之后才复制，避免比较失败时也分配内存
+		n := new
+		if v.p.CompareAndSwap(p, &n) {
+			return true
+		}
+	}
+}
+
+// Update 使用 fn 根据旧值计算出新值并写入，写入期间被其他 goroutine 修改时会重新计算
+// fn 可能被调用多次，所以不能有副作用
+func (v *Value[T]) Update(fn func(old T) T) (new T) {
+	for {
+		p := v.p.Load()
+		var old T
+		if p != nil {
+			old = *p
+		}
+		new = fn(old)
+		if v.p.CompareAndSwap(p, &new) {
+			return
+		}
+	}
+}
+
+func (v *Value[T]) equal() func(a, b T) bool {
+	if eq := v.eq.Load(); eq != nil {
+		return *eq
+	}
+	// 并发生成的比较函数是一样的，谁写入都可以
+	eq := newEqual[T]()
+	v.eq.Store(eq)
+	return *eq
+}
+
+// newEqual 根据 T 的类型生成比较函数，只有这里用到反射
+func newEqual[T any]() *func(a, b T) bool {
+	var eq func(a, b T) bool
+	// 常见的基本类型直接比较，不需要转换成 any
+	switch any(*new(T)).(type) {
+	case bool:
+		eq = equalFunc[T, bool]()
+	case int:
+		eq = equalFunc[T, int]()
+	case int8:
+		eq = equalFunc[T, int8]()
+	case int16:
+		eq = equalFunc[T, int16]()
+	case int32:
+		eq = equalFunc[T, int32]()
+	case int64:
+		eq = equalFunc[T, int64]()
+	case uint:
+		eq = equalFunc[T, uint]()
+	case uint8:
+		eq = equalFunc[T, uint8]()
+	case uint16:
+		eq = equalFunc[T, uint16]()
+	case uint32:
+		eq = equalFunc[T, uint32]()
+	case uint64:
+		eq = equalFunc[T, uint64]()
+	case uintptr:
+		eq = equalFunc[T, uintptr]()
+	case float32:
+		eq = equalFunc[T, float32]()
+	case float64:
+		eq = equalFunc[T, float64]()
+	case string:
+		eq = equalFunc[T, string]()
+	}
+	if eq != nil {
+		return &eq
+	}
+	switch t := reflect.TypeOf((*T)(nil)).Elem(); {
+	case !t.Comparable():
+		eq = func(a, b T) bool {
+			return false
+		}
+	case t.Kind() == reflect.Interface:
+		// 动态类型不同的时候直接不相等，相同的时候才需要检查是否可比较
+		eq = func(a, b T) bool {
+			x, y := any(a), any(b)
+			if x == nil || y == nil {
+				return x == y
+			}
+			dt := reflect.TypeOf(x)
+			return dt == reflect.TypeOf(y) && dt.Comparable() && x == y
+		}
+	case hasInterface(t):
+		// 字段或者元素里接口的动态类型可能不可比较，比较时会 panic
+		eq = func(a, b T) (res bool) {
+			defer func() {
+				if recover() != nil {
+					res = false
+				}
+			}()
+			return any(a) == any(b)
+		}
+	default:
+		eq = func(a, b T) bool {
+			return any(a) == any(b)
+		}
+	}
+	return &eq
+}
+
+// equalFunc 返回 E 的比较函数，调用方需要保证 T 就是 E
+func equalFunc[T any, E comparable]() func(a, b T) bool {
+	return any(func(a, b E) bool {
+		return a == b
+	}).(func(a, b T) bool)
+}
+
+// hasInterface 判断可比较的类型 t 本身或者它的字段、元素里面有没有接口
+func hasInterface(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Array:
+		return hasInterface(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if hasInterface(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
 }
@@ -17,6 +17,7 @@
 package atomicx
 
 import (
+	"sync"
 	"sync/atomic"
 	"testing"
 
@@ -149,6 +150,66 @@ func TestValue_Store_Load(t *testing.T) {
 	}
 }
 
+func TestValue_CompareAndSwap_NotComparable(t *testing.T) {
+	val := NewValueOf[[]int]([]int{1})
+	assert.False(t, val.CompareAndSwap([]int{1}, []int{2}))
+	assert.Equal(t, []int{1}, val.Load())
+
+	// 动态类型不可比较
+	anyVal := NewValueOf[any](map[string]int{"a": 1})
+	assert.False(t, anyVal.CompareAndSwap(map[string]int{"a": 1}, 2))
+	assert.True(t, NewValueOf[any](1).CompareAndSwap(1, 2))
+}
+
+func TestValue_CompareAndSwap_Types(t *testing.T) {
+	type MyInt int
+	assert.True(t, NewValueOf[MyInt](1).CompareAndSwap(1, 2))
+	assert.False(t, NewValueOf[MyInt](1).CompareAndSwap(2, 3))
+	assert.True(t, NewValueOf[string]("a").CompareAndSwap("a", "b"))
+	assert.True(t, NewValueOf[Article](Article{Content: "a"}).CompareAndSwap(Article{Content: "a"}, Article{}))
+
+	// 结构体的字段里有接口，动态类型不可比较
+	type wrapper struct {
+		val any
+	}
+	val := NewValueOf[wrapper](wrapper{val: []int{1}})
+	assert.False(t, val.CompareAndSwap(wrapper{val: []int{1}}, wrapper{}))
+	assert.True(t, NewValueOf[wrapper](wrapper{val: 1}).CompareAndSwap(wrapper{val: 1}, wrapper{}))
+
+	var zero Value[any]
+	assert.True(t, zero.CompareAndSwap(nil, 1))
+	assert.Equal(t, 1, zero.Load())
+}
+
+func TestValue_ZeroValue(t *testing.T) {
+	var val Value[int]
+	assert.Equal(t, 0, val.Load())
+	assert.True(t, val.CompareAndSwap(0, 1))
+	assert.False(t, val.CompareAndSwap(0, 2))
+	assert.Equal(t, 1, val.Swap(3))
+	assert.Equal(t, 3, val.Load())
+}
+
+func TestValue_Update(t *testing.T) {
+	val := NewValueOf[Article](Article{Content: "a"})
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			val.Update(func(old Article) Article {
+				return Article{Content: old.Content + "a"}
+			})
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 101, len(val.Load().Content))
+	res := val.Update(func(old Article) Article {
+		return Article{Content: "b"}
+	})
+	assert.Equal(t, Article{Content: "b"}, res)
+}
+
 func BenchmarkValue_Load(b *testing.B) {
 	b.Run("Value", func(b *testing.B) {
 		val := NewValueOf[int](123)
@@ -181,6 +242,23 @@ func BenchmarkValue_Store(b *testing.B) {
 			val.Store(123)
 		}
 	})
+
+	// T 是指针的时候原生 atomic.Value 直接保存指针，不需要分配内存
+	b.Run("pointer Value", func(b *testing.B) {
+		val := NewValue[*Article]()
+		a := &Article{Content: "a"}
+		for i := 0; i < b.N; i++ {
+			val.Store(a)
+		}
+	})
+
+	b.Run("pointer atomic Value", func(b *testing.B) {
+		val := &atomic.Value{}
+		a := &Article{Content: "a"}
+		for i := 0; i < b.N; i++ {
+			val.Store(a)
+		}
+	})
 }
 
 func BenchmarkValue_Swap(b *testing.B) {
@@ -198,6 +276,23 @@ func BenchmarkValue_Swap(b *testing.B) {
 			_ = val.Swap(456)
 		}
 	})
+
+	b.Run("pointer Value", func(b *testing.B) {
+		a := &Article{Content: "a"}
+		val := NewValueOf[*Article](a)
+		for i := 0; i < b.N; i++ {
+			_ = val.Swap(a)
+		}
+	})
+
+	b.Run("pointer atomic Value", func(b *testing.B) {
+		a := &Article{Content: "a"}
+		val := &atomic.Value{}
+		val.Store(a)
+		for i := 0; i < b.N; i++ {
+			_ = val.Swap(a)
+		}
+	})
 }
 
 func BenchmarkValue_CompareAndSwap(b *testing.B) {
@@ -233,3 +328,38 @@ func BenchmarkValue_CompareAndSwap(b *testing.B) {
 		})
 	})
 }
+
+// 结构体和接口走的是 any 比较，比较函数在创建时根据类型生成
+func BenchmarkValue_CompareAndSwap_Fail(b *testing.B) {
+	b.Run("struct", func(b *testing.B) {
+		b.Run("Value", func(b *testing.B) {
+			val := NewValueOf[Article](Article{Content: "a"})
+			for i := 0; i < b.N; i++ {
+				_ = val.CompareAndSwap(Article{Content: "b"}, Article{Content: "c"})
+			}
+		})
+		b.Run("atomic Value", func(b *testing.B) {
+			val := &atomic.Value{}
+			val.Store(Article{Content: "a"})
+			for i := 0; i < b.N; i++ {
+				_ = val.CompareAndSwap(Article{Content: "b"}, Article{Content: "c"})
+			}
+		})
+	})
+
+	b.Run("interface", func(b *testing.B) {
+		b.Run("Value", func(b *testing.B) {
+			val := NewValueOf[any](123)
+			for i := 0; i < b.N; i++ {
+				_ = val.CompareAndSwap(-1, 100)
+			}
+		})
+		b.Run("atomic Value", func(b *testing.B) {
+			val := &atomic.Value{}
+			val.Store(123)
+			for i := 0; i < b.N; i++ {
+				_ = val.CompareAndSwap(-1, 100)
+			}
+		})
+	})
+}
@@ -0,0 +1,72 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package atomicx
+
+import (
+	"sync/atomic"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Int 泛型的原子整数，支持所有的整数类型
+// 内部统一用 uint64 存储，溢出时的行为和 T 本身的溢出一致
+// 零值可以直接使用
+type Int[T constraints.Integer] struct {
+	val atomic.Uint64
+}
+
+// NewInt 会使用传入的值来创建一个 Int 对象
+func NewInt[T constraints.Integer](t T) *Int[T] {
+	i := &Int[T]{}
+	i.val.Store(uint64(t))
+	return i
+}
+
+func (i *Int[T]) Load() T {
+	return T(i.val.Load())
+}
+
+func (i *Int[T]) Store(val T) {
+	i.val.Store(uint64(val))
+}
+
+func (i *Int[T]) Swap(new T) (old T) {
+	return T(i.val.Swap(uint64(new)))
+}
+
+// Add 加上 delta 并返回新值
+func (i *Int[T]) Add(delta T) (new T) {
+	return T(i.val.Add(uint64(delta)))
+}
+
+// Sub 减去 delta 并返回新值
+func (i *Int[T]) Sub(delta T) (new T) {
+	return i.Add(-delta)
+}
+
+func (i *Int[T]) CompareAndSwap(old, new T) (swapped bool) {
+	for {
+		// 高位可能因为 T 的溢出和 uint64(old) 不一致，所以按 T 比较
+		raw := i.val.Load()
+		if T(raw) != old {
+			return false
+		}
+		if i.val.CompareAndSwap(raw, uint64(new)) {
+			return true
+		}
+	}
+}
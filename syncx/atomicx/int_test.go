@@ -0,0 +1,100 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package atomicx
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInt(t *testing.T) {
+	i := NewInt[int64](10)
+	assert.Equal(t, int64(15), i.Add(5))
+	assert.Equal(t, int64(-5), i.Sub(20))
+	assert.Equal(t, int64(-5), i.Swap(3))
+	assert.False(t, i.CompareAndSwap(4, 5))
+	assert.True(t, i.CompareAndSwap(3, 5))
+	assert.Equal(t, int64(5), i.Load())
+	i.Store(-1)
+	assert.Equal(t, int64(-1), i.Load())
+}
+
+func TestInt_Overflow(t *testing.T) {
+	i := NewInt[int8](math.MaxInt8)
+	assert.Equal(t, int8(math.MinInt8), i.Add(1))
+	// 溢出之后依然可以正常比较
+	assert.True(t, i.CompareAndSwap(math.MinInt8, -1))
+	assert.Equal(t, int8(-2), i.Sub(1))
+
+	var u Int[uint32]
+	assert.Equal(t, uint32(math.MaxUint32), u.Sub(1))
+	assert.Equal(t, uint32(0), u.Add(1))
+	assert.True(t, u.CompareAndSwap(0, 1))
+}
+
+func TestInt_Concurrent(t *testing.T) {
+	var i Int[int]
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				i.Add(2)
+				i.Sub(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10000, i.Load())
+}
+
+func BenchmarkInt_Add(b *testing.B) {
+	b.Run("Int", func(b *testing.B) {
+		var val Int[int64]
+		for i := 0; i < b.N; i++ {
+			_ = val.Add(1)
+		}
+	})
+
+	b.Run("atomic Int64", func(b *testing.B) {
+		var val atomic.Int64
+		for i := 0; i < b.N; i++ {
+			_ = val.Add(1)
+		}
+	})
+}
+
+func BenchmarkInt_CompareAndSwap(b *testing.B) {
+	b.Run("Int", func(b *testing.B) {
+		var val Int[int64]
+		for i := 0; i < b.N; i++ {
+			_ = val.CompareAndSwap(int64(i), int64(i+1))
+		}
+	})
+
+	b.Run("atomic Int64", func(b *testing.B) {
+		var val atomic.Int64
+		for i := 0; i < b.N; i++ {
+			_ = val.CompareAndSwap(int64(i), int64(i+1))
+		}
+	})
+}
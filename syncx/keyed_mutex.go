@@ -0,0 +1,92 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import (
+	"context"
+	"sync"
+)
+
+type refMutex struct {
+	mutex chanMutex
+	// ref 持有和等待这把锁的 goroutine 数量，为 0 时从 map 中删除
+	ref int
+}
+
+// KeyedMutex 每个 key 一把锁，同一个 key 的操作串行执行，不同的 key 互不影响
+// 锁按需创建，没有 goroutine 持有或者等待时立刻释放，适合 key 的数量很多但同时活跃的很少的场景
+// 零值可以直接使用
+type KeyedMutex[K comparable] struct {
+	mutex sync.Mutex
+	locks map[K]*refMutex
+}
+
+func (k *KeyedMutex[K]) acquire(key K) *refMutex {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if k.locks == nil {
+		k.locks = make(map[K]*refMutex)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &refMutex{mutex: newChanMutex()}
+		k.locks[key] = l
+	}
+	l.ref++
+	return l
+}
+
+func (k *KeyedMutex[K]) release(key K, l *refMutex) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	l.ref--
+	if l.ref == 0 {
+		delete(k.locks, key)
+	}
+}
+
+func (k *KeyedMutex[K]) Lock(key K) {
+	k.acquire(key).mutex.lock()
+}
+
+// TryLock 加锁，直到加锁成功或者 ctx 结束
+func (k *KeyedMutex[K]) TryLock(ctx context.Context, key K) error {
+	l := k.acquire(key)
+	if err := l.mutex.tryLock(ctx); err != nil {
+		k.release(key, l)
+		return err
+	}
+	return nil
+}
+
+func (k *KeyedMutex[K]) Unlock(key K) {
+	k.mutex.Lock()
+	l, ok := k.locks[key]
+	k.mutex.Unlock()
+	if !ok {
+		panic("syncx: unlock of unlocked key")
+	}
+	l.mutex.unlock()
+	k.release(key, l)
+}
+
+// Len 当前被持有或者等待中的 key 的数量
+func (k *KeyedMutex[K]) Len() int {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return len(k.locks)
+}
@@ -0,0 +1,82 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	var l KeyedMutex[string]
+	cnts := make(map[string]*int, 3)
+	for i := 0; i < 3; i++ {
+		cnts[strconv.Itoa(i)] = new(int)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		key := strconv.Itoa(i % 3)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Lock(key)
+				*cnts[key]++
+				l.Unlock(key)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, cnt := range cnts {
+		assert.Equal(t, 1000, *cnt)
+	}
+	// 空闲的 key 已经被释放
+	assert.Equal(t, 0, l.Len())
+}
+
+func TestKeyedMutex_TryLock(t *testing.T) {
+	var l KeyedMutex[int]
+	l.Lock(1)
+	// 不同的 key 互不影响
+	require.NoError(t, l.TryLock(context.Background(), 2))
+	assert.Equal(t, 2, l.Len())
+	l.Unlock(2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l.TryLock(ctx, 1))
+	assert.Equal(t, 1, l.Len())
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		l.Unlock(1)
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, l.TryLock(ctx, 1))
+	l.Unlock(1)
+	assert.Equal(t, 0, l.Len())
+	assert.Panics(t, func() {
+		l.Unlock(1)
+	})
+}
@@ -0,0 +1,216 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrKeyNotFound = errors.New("syncx: BatchFn 没有返回 key 对应的值")
+
+// BatchFn 批量加载 keys 对应的值，返回的 map 中没有的 key 会得到 ErrKeyNotFound
+type BatchFn[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+type loaderBatch[K comparable, V any] struct {
+	keys []K
+	// index 用于去重
+	index map[K]struct{}
+	// ctx 第一个调用方的 ctx，去掉了取消信号
+	ctx        context.Context
+	timer      *time.Timer
+	dispatched bool
+	done       chan struct{}
+	res        map[K]V
+	err        error
+}
+
+// Loader DataLoader 模式的批量加载器
+// 一个时间窗口内或者凑够一批的 Load 会合并成一次 BatchFn 调用，重复的 key 只加载一次
+// 开启缓存时加载过的 key 直接返回之前的结果，一般每个请求创建一个 Loader，缓存随请求一起释放
+type Loader[K comparable, V any] struct {
+	fn       BatchFn[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mutex sync.Mutex
+	batch *loaderBatch[K, V]
+	// cache 为 nil 表示不缓存，key 对应加载它的批次
+	cache map[K]*loaderBatch[K, V]
+}
+
+// LoaderOption Loader 的可选配置
+type LoaderOption[K comparable, V any] func(l *Loader[K, V])
+
+// WithWait 等待凑批的时间，默认 1ms
+func WithWait[K comparable, V any](wait time.Duration) LoaderOption[K, V] {
+	return func(l *Loader[K, V]) {
+		l.wait = wait
+	}
+}
+
+// WithMaxBatch 一批最多的 key 数量，凑够了立刻加载，默认 100，<= 0 表示不限制
+func WithMaxBatch[K comparable, V any](maxBatch int) LoaderOption[K, V] {
+	return func(l *Loader[K, V]) {
+		l.maxBatch = maxBatch
+	}
+}
+
+// WithCache 缓存加载成功的结果
+func WithCache[K comparable, V any]() LoaderOption[K, V] {
+	return func(l *Loader[K, V]) {
+		l.cache = make(map[K]*loaderBatch[K, V])
+	}
+}
+
+func NewLoader[K comparable, V any](fn BatchFn[K, V], opts ...LoaderOption[K, V]) *Loader[K, V] {
+	l := &Loader[K, V]{
+		fn:       fn,
+		wait:     time.Millisecond,
+		maxBatch: 100,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Load 加载 key 对应的值，阻塞直到所在的批次加载完成或者 ctx 结束
+// ctx 结束只影响当前调用方，不会取消批量加载
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	b := l.enqueue(ctx, key)
+	select {
+	case <-b.done:
+		return b.result(key)
+	case <-ctx.Done():
+		var v V
+		return v, ctx.Err()
+	}
+}
+
+// LoadMany 加载多个 key，任意一个 key 加载失败都会返回 error
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) (map[K]V, error) {
+	batches := make([]*loaderBatch[K, V], len(keys))
+	for i, key := range keys {
+		batches[i] = l.enqueue(ctx, key)
+	}
+	res := make(map[K]V, len(keys))
+	for i, b := range batches {
+		select {
+		case <-b.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		v, err := b.result(keys[i])
+		if err != nil {
+			return nil, err
+		}
+		res[keys[i]] = v
+	}
+	return res, nil
+}
+
+// Clear 删除 key 的缓存
+func (l *Loader[K, V]) Clear(key K) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	delete(l.cache, key)
+}
+
+// enqueue 把 key 加入当前批次，返回 key 所在的批次
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K) *loaderBatch[K, V] {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if b, ok := l.cache[key]; ok {
+		return b
+	}
+	b := l.batch
+	if b == nil {
+		b = &loaderBatch[K, V]{
+			index: make(map[K]struct{}),
+			ctx:   context.WithoutCancel(ctx),
+			done:  make(chan struct{}),
+		}
+		b.timer = time.AfterFunc(l.wait, func() {
+			l.dispatch(b)
+		})
+		l.batch = b
+	}
+	if _, ok := b.index[key]; !ok {
+		b.index[key] = struct{}{}
+		b.keys = append(b.keys, key)
+	}
+	if l.cache != nil {
+		l.cache[key] = b
+	}
+	if l.maxBatch > 0 && len(b.keys) >= l.maxBatch {
+		b.timer.Stop()
+		l.batch = nil
+		b.dispatched = true
+		go l.load(b)
+	}
+	return b
+}
+
+func (l *Loader[K, V]) dispatch(b *loaderBatch[K, V]) {
+	l.mutex.Lock()
+	if b.dispatched {
+		l.mutex.Unlock()
+		return
+	}
+	b.dispatched = true
+	if l.batch == b {
+		l.batch = nil
+	}
+	l.mutex.Unlock()
+	l.load(b)
+}
+
+func (l *Loader[K, V]) load(b *loaderBatch[K, V]) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.err = fmt.Errorf("syncx: BatchFn panic: %v", r)
+		}
+		if b.err != nil && l.cache != nil {
+			// 加载失败的结果不缓存
+			l.mutex.Lock()
+			for _, key := range b.keys {
+				if l.cache[key] == b {
+					delete(l.cache, key)
+				}
+			}
+			l.mutex.Unlock()
+		}
+		close(b.done)
+	}()
+	b.res, b.err = l.fn(b.ctx, b.keys)
+}
+
+func (b *loaderBatch[K, V]) result(key K) (V, error) {
+	if b.err != nil {
+		var v V
+		return v, b.err
+	}
+	v, ok := b.res[key]
+	if !ok {
+		return v, fmt.Errorf("%w: %v", ErrKeyNotFound, key)
+	}
+	return v, nil
+}
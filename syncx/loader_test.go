@@ -0,0 +1,159 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRecorder 记录每次 BatchFn 调用收到的 keys
+type batchRecorder struct {
+	mutex   sync.Mutex
+	batches [][]int
+	err     error
+}
+
+func (r *batchRecorder) load(ctx context.Context, keys []int) (map[int]string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	sorted := append([]int(nil), keys...)
+	sort.Ints(sorted)
+	r.batches = append(r.batches, sorted)
+	if r.err != nil {
+		return nil, r.err
+	}
+	res := make(map[int]string, len(keys))
+	for _, key := range keys {
+		// 负数模拟不存在的数据
+		if key >= 0 {
+			res[key] = strconv.Itoa(key)
+		}
+	}
+	return res, nil
+}
+
+func loadConcurrently(t *testing.T, l *Loader[int, string], keys []int) {
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Add(1)
+		go func(key int) {
+			defer wg.Done()
+			v, err := l.Load(context.Background(), key)
+			if key < 0 {
+				assert.ErrorIs(t, err, ErrKeyNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, strconv.Itoa(key), v)
+		}(key)
+	}
+	wg.Wait()
+}
+
+func TestLoader_Load(t *testing.T) {
+	r := &batchRecorder{}
+	l := NewLoader[int, string](r.load, WithWait[int, string](20*time.Millisecond))
+	loadConcurrently(t, l, []int{1, 2, 3, 2, 1, -1})
+	// 合并成一批，并且去重
+	assert.Equal(t, [][]int{{-1, 1, 2, 3}}, r.batches)
+
+	// 没有缓存，再次加载
+	loadConcurrently(t, l, []int{1})
+	assert.Equal(t, [][]int{{-1, 1, 2, 3}, {1}}, r.batches)
+}
+
+func TestLoader_MaxBatch(t *testing.T) {
+	r := &batchRecorder{}
+	l := NewLoader[int, string](r.load,
+		WithWait[int, string](time.Hour), WithMaxBatch[int, string](3))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	res, err := l.LoadMany(ctx, []int{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{1: "1", 2: "2", 3: "3", 4: "4", 5: "5", 6: "6"}, res)
+	// 两批并发加载，顺序不确定
+	assert.ElementsMatch(t, [][]int{{1, 2, 3}, {4, 5, 6}}, r.batches)
+}
+
+func TestLoader_Cache(t *testing.T) {
+	r := &batchRecorder{}
+	l := NewLoader[int, string](r.load, WithCache[int, string]())
+	loadConcurrently(t, l, []int{1, 2})
+	loadConcurrently(t, l, []int{1, 2, 3})
+	assert.Equal(t, [][]int{{1, 2}, {3}}, r.batches)
+
+	l.Clear(1)
+	loadConcurrently(t, l, []int{1, 2})
+	assert.Equal(t, [][]int{{1, 2}, {3}, {1}}, r.batches)
+
+	// 加载失败的结果不缓存
+	r.err = errors.New("mock error")
+	_, err := l.Load(context.Background(), 4)
+	assert.Equal(t, errors.New("mock error"), err)
+	r.err = nil
+	v, err := l.Load(context.Background(), 4)
+	require.NoError(t, err)
+	assert.Equal(t, "4", v)
+}
+
+func TestLoader_Cancel(t *testing.T) {
+	block := make(chan struct{})
+	l := NewLoader[int, string](func(ctx context.Context, keys []int) (map[int]string, error) {
+		<-block
+		// 调用方取消不影响批量加载
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return map[int]string{1: "1"}, nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := l.Load(ctx, 1)
+		assert.Equal(t, context.DeadlineExceeded, err)
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		v, err := l.Load(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "1", v)
+	}()
+	time.Sleep(100 * time.Millisecond)
+	close(block)
+	wg.Wait()
+}
+
+func TestLoader_Panic(t *testing.T) {
+	l := NewLoader[int, string](func(ctx context.Context, keys []int) (map[int]string, error) {
+		panic("mock panic")
+	})
+	_, err := l.Load(context.Background(), 1)
+	assert.EqualError(t, err, "syncx: BatchFn panic: mock panic")
+}
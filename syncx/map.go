@@ -0,0 +1,83 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import "sync"
+
+// Map 是对 sync.Map 的泛型封装，零值可以直接使用
+type Map[K comparable, V any] struct {
+	m sync.Map
+}
+
+// Load 返回 key 对应的值，ok 表示 key 是否存在
+func (m *Map[K, V]) Load(key K) (value V, ok bool) {
+	val, ok := m.m.Load(key)
+	if ok {
+		// 存入的是值为 nil 的接口时，断言会失败，这里返回零值
+		value, _ = val.(V)
+	}
+	return value, ok
+}
+
+func (m *Map[K, V]) Store(key K, value V) {
+	m.m.Store(key, value)
+}
+
+// LoadOrStore key 存在时返回已有的值，loaded 为 true，否则存入 value 并返回 value
+func (m *Map[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	val, loaded := m.m.LoadOrStore(key, value)
+	actual, _ = val.(V)
+	return actual, loaded
+}
+
+// LoadOrStoreFunc key 存在时返回已有的值，否则调用 fn 构造值再存入
+// 并发调用时 fn 可能被调用多次，但只有一个结果会被存入，其他的结果会被丢弃
+// fn 返回 error 时不会存入
+func (m *Map[K, V]) LoadOrStoreFunc(key K, fn func() (V, error)) (actual V, loaded bool, err error) {
+	val, ok := m.Load(key)
+	if ok {
+		return val, true, nil
+	}
+	val, err = fn()
+	if err != nil {
+		return val, false, err
+	}
+	actual, loaded = m.LoadOrStore(key, val)
+	return actual, loaded, nil
+}
+
+// LoadAndDelete 删除 key 并返回删除之前的值
+func (m *Map[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	val, loaded := m.m.LoadAndDelete(key)
+	if loaded {
+		value, _ = val.(V)
+	}
+	return value, loaded
+}
+
+func (m *Map[K, V]) Delete(key K) {
+	m.m.Delete(key)
+}
+
+// Range 遍历所有的键值对，fn 返回 false 时停止遍历
+func (m *Map[K, V]) Range(fn func(key K, value V) bool) {
+	m.m.Range(func(key, value any) bool {
+		k, _ := key.(K)
+		v, _ := value.(V)
+		return fn(k, v)
+	})
+}
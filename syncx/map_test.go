@@ -0,0 +1,146 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMap(t *testing.T) {
+	var m Map[string, int]
+	val, ok := m.Load("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, val)
+
+	m.Store("a", 1)
+	val, ok = m.Load("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, val)
+
+	actual, loaded := m.LoadOrStore("a", 2)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, actual)
+	actual, loaded = m.LoadOrStore("b", 2)
+	assert.False(t, loaded)
+	assert.Equal(t, 2, actual)
+
+	res := make(map[string]int)
+	m.Range(func(key string, value int) bool {
+		res[key] = value
+		return true
+	})
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, res)
+
+	val, loaded = m.LoadAndDelete("a")
+	assert.True(t, loaded)
+	assert.Equal(t, 1, val)
+	_, loaded = m.LoadAndDelete("a")
+	assert.False(t, loaded)
+
+	m.Delete("b")
+	_, ok = m.Load("b")
+	assert.False(t, ok)
+}
+
+func TestMap_NilValue(t *testing.T) {
+	var m Map[string, error]
+	m.Store("a", nil)
+	val, ok := m.Load("a")
+	assert.True(t, ok)
+	assert.Nil(t, val)
+	actual, loaded := m.LoadOrStore("a", errors.New("mock error"))
+	assert.True(t, loaded)
+	assert.Nil(t, actual)
+}
+
+func TestMap_LoadOrStoreFunc(t *testing.T) {
+	testCases := []struct {
+		name       string
+		before     func(m *Map[string, int])
+		fn         func() (int, error)
+		wantVal    int
+		wantLoaded bool
+		wantErr    error
+	}{
+		{
+			name: "已经存在",
+			before: func(m *Map[string, int]) {
+				m.Store("a", 1)
+			},
+			fn: func() (int, error) {
+				panic("不应该被调用")
+			},
+			wantVal:    1,
+			wantLoaded: true,
+		},
+		{
+			name:   "不存在",
+			before: func(m *Map[string, int]) {},
+			fn: func() (int, error) {
+				return 2, nil
+			},
+			wantVal: 2,
+		},
+		{
+			name:   "构造失败",
+			before: func(m *Map[string, int]) {},
+			fn: func() (int, error) {
+				return 0, errors.New("mock error")
+			},
+			wantErr: errors.New("mock error"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var m Map[string, int]
+			tc.before(&m)
+			val, loaded, err := m.LoadOrStoreFunc("a", tc.fn)
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantVal, val)
+			assert.Equal(t, tc.wantLoaded, loaded)
+			_, ok := m.Load("a")
+			assert.Equal(t, err == nil, ok)
+		})
+	}
+}
+
+func TestMap_LoadOrStoreFuncConcurrent(t *testing.T) {
+	var m Map[string, *int]
+	var wg sync.WaitGroup
+	results := make([]*int, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			val, _, err := m.LoadOrStoreFunc("a", func() (*int, error) {
+				return new(int), nil
+			})
+			require.NoError(t, err)
+			results[i] = val
+		}(i)
+	}
+	wg.Wait()
+	// 所有的 goroutine 拿到的都是同一个值
+	for _, res := range results {
+		assert.Same(t, results[0], res)
+	}
+}
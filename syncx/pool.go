@@ -0,0 +1,64 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import "sync"
+
+// Pool 是对 sync.Pool 的泛型封装
+type Pool[T any] struct {
+	p     sync.Pool
+	reset func(T)
+}
+
+// PoolOption Pool 的可选配置
+type PoolOption[T any] func(p *Pool[T])
+
+// WithReset 放回 Pool 之前调用 fn 重置对象，例如清空 bytes.Buffer
+func WithReset[T any](fn func(T)) PoolOption[T] {
+	return func(p *Pool[T]) {
+		p.reset = fn
+	}
+}
+
+// NewPool 创建 Pool，factory 用于 Pool 为空的时候创建新的对象
+func NewPool[T any](factory func() T, opts ...PoolOption[T]) *Pool[T] {
+	p := &Pool[T]{
+		p: sync.Pool{
+			New: func() any {
+				return factory()
+			},
+		},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Get 从 Pool 中取出一个对象，Pool 为空时调用 factory 创建
+func (p *Pool[T]) Get() T {
+	t, _ := p.p.Get().(T)
+	return t
+}
+
+// Put 把对象放回 Pool，设置了 reset 时会先重置
+func (p *Pool[T]) Put(t T) {
+	if p.reset != nil {
+		p.reset(t)
+	}
+	p.p.Put(t)
+}
@@ -0,0 +1,302 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wkRonin/toolkit/containerx/queue"
+	"github.com/wkRonin/toolkit/logger"
+)
+
+var (
+	ErrPoolClosed = errors.New("pool: 协程池已经关闭")
+	ErrPoolFull   = errors.New("pool: 协程池已满")
+)
+
+// Task 提交到协程池的任务，ctx 在协程池强制关闭的时候会被取消
+type Task func(ctx context.Context)
+
+type task struct {
+	fn       Task
+	priority int
+	// seq 提交的顺序，优先级相同时先提交的先执行
+	seq int64
+}
+
+// taskQueue 排队中的任务，容量由 WorkerPool 控制，入队永远不会阻塞
+type taskQueue interface {
+	enqueue(t task)
+	dequeue(ctx context.Context) (task, error)
+}
+
+type chanQueue chan task
+
+func (c chanQueue) enqueue(t task) {
+	c <- t
+}
+
+func (c chanQueue) dequeue(ctx context.Context) (task, error) {
+	select {
+	case t := <-c:
+		return t, nil
+	case <-ctx.Done():
+		return task{}, ctx.Err()
+	}
+}
+
+type priorityQueue struct {
+	pq *queue.ConcurrentPriorityQueue[task]
+}
+
+func (p priorityQueue) enqueue(t task) {
+	_ = p.pq.Enqueue(context.Background(), t)
+}
+
+func (p priorityQueue) dequeue(ctx context.Context) (task, error) {
+	return p.pq.Dequeue(ctx)
+}
+
+// WorkerPool 固定数量 worker 的协程池
+// 同时执行的任务数不超过 worker 数量，排队的任务数不超过队列大小
+// 任务 panic 会被恢复并记录日志，不会影响 worker
+type WorkerPool struct {
+	// slots 协程池中的任务数，包括排队中和执行中的，满了之后 Submit 阻塞或者直接失败
+	slots    chan struct{}
+	queue    taskQueue
+	seq      atomic.Int64
+	failFast bool
+	l        logger.Logger
+
+	mutex  sync.RWMutex
+	closed bool
+	// pending 还没有执行完的任务数，包括排队中和执行中的
+	pending atomic.Int64
+	// drained 关闭之后 pending 归零时关闭，Shutdown 等待它而不是等待所有任务
+	// 这样超时返回之后不会留下阻塞的协程，丢弃的任务也不需要再计数
+	drained   chan struct{}
+	drainOnce sync.Once
+	ctx       context.Context
+	cancel    context.CancelFunc
+	// done 协程池彻底停止之后关闭，唤醒阻塞中的 Submit
+	done chan struct{}
+}
+
+type options struct {
+	queueSize int
+	failFast  bool
+	priority  bool
+	l         logger.Logger
+}
+
+type Option func(o *options)
+
+// WithQueueSize 排队任务的数量上限，默认和 worker 数量相同
+func WithQueueSize(size int) Option {
+	return func(o *options) {
+		o.queueSize = size
+	}
+}
+
+// WithFailFast 协程池满的时候 Submit 直接返回 ErrPoolFull，默认阻塞等待
+func WithFailFast() Option {
+	return func(o *options) {
+		o.failFast = true
+	}
+}
+
+// WithPriority 使用优先队列排队，优先级高的任务先执行，默认先进先出
+func WithPriority() Option {
+	return func(o *options) {
+		o.priority = true
+	}
+}
+
+// WithLogger 记录任务 panic 的日志，默认不记录
+func WithLogger(l logger.Logger) Option {
+	return func(o *options) {
+		o.l = l
+	}
+}
+
+// NewWorkerPool 创建协程池并启动 workers 个 worker
+func NewWorkerPool(workers int, opts ...Option) *WorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	o := &options{
+		queueSize: workers,
+		l:         &logger.NopLogger{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.queueSize < 0 {
+		o.queueSize = 0
+	}
+	// 刚被 worker 取出但还没有释放的任务也会占用队列，所以按照总的任务数来分配容量
+	capacity := workers + o.queueSize
+	var q taskQueue = make(chanQueue, capacity)
+	if o.priority {
+		q = priorityQueue{
+			pq: queue.NewConcurrentPriorityQueue[task](capacity, func(src, dst task) bool {
+				if src.priority != dst.priority {
+					return src.priority > dst.priority
+				}
+				return src.seq < dst.seq
+			}),
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &WorkerPool{
+		slots:    make(chan struct{}, capacity),
+		queue:    q,
+		failFast: o.failFast,
+		l:        o.l,
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		drained:  make(chan struct{}),
+	}
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// Submit 提交任务，等价于优先级为 0 的 SubmitWithPriority
+func (p *WorkerPool) Submit(ctx context.Context, fn Task) error {
+	return p.SubmitWithPriority(ctx, 0, fn)
+}
+
+// SubmitWithPriority 提交任务，priority 越大越先执行，只在 WithPriority 时生效
+// 协程池满的时候，默认阻塞直到有空位或者 ctx 结束，WithFailFast 时直接返回 ErrPoolFull
+// 协程池关闭之后返回 ErrPoolClosed
+func (p *WorkerPool) SubmitWithPriority(ctx context.Context, priority int, fn Task) error {
+	if err := p.acquire(ctx); err != nil {
+		return err
+	}
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	if p.closed {
+		<-p.slots
+		return ErrPoolClosed
+	}
+	p.pending.Add(1)
+	p.queue.enqueue(task{
+		fn:       fn,
+		priority: priority,
+		seq:      p.seq.Add(1),
+	})
+	return nil
+}
+
+func (p *WorkerPool) acquire(ctx context.Context) error {
+	if p.failFast {
+		select {
+		case p.slots <- struct{}{}:
+			return nil
+		case <-p.done:
+			return ErrPoolClosed
+		default:
+			return ErrPoolFull
+		}
+	}
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-p.done:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *WorkerPool) work() {
+	for {
+		t, err := p.queue.dequeue(p.ctx)
+		if err != nil {
+			return
+		}
+		// 强制关闭之后取到的任务属于被丢弃的任务
+		if p.ctx.Err() != nil {
+			return
+		}
+		p.run(t)
+		<-p.slots
+		p.finish()
+	}
+}
+
+func (p *WorkerPool) finish() {
+	if p.pending.Add(-1) != 0 {
+		return
+	}
+	p.mutex.RLock()
+	closed := p.closed
+	p.mutex.RUnlock()
+	if closed {
+		p.markDrained()
+	}
+}
+
+func (p *WorkerPool) markDrained() {
+	p.drainOnce.Do(func() {
+		close(p.drained)
+	})
+}
+
+func (p *WorkerPool) run(t task) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.l.Error("pool: 任务 panic",
+				logger.String("panic", fmt.Sprintf("%v", r)),
+				logger.String("stack", string(debug.Stack())))
+		}
+	}()
+	t.fn(p.ctx)
+}
+
+// Shutdown 不再接收新的任务，等待排队中和执行中的任务执行完
+// ctx 结束时还没有执行完的话，取消传给任务的 ctx，丢弃排队中的任务，并返回 ctx.Err()
+func (p *WorkerPool) Shutdown(ctx context.Context) error {
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return ErrPoolClosed
+	}
+	p.closed = true
+	p.mutex.Unlock()
+	// 关闭之前任务已经全部执行完的话，不会再有 finish 来关闭 drained
+	if p.pending.Load() == 0 {
+		p.markDrained()
+	}
+
+	defer close(p.done)
+	defer p.cancel()
+	select {
+	case <-p.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
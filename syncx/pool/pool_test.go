@@ -0,0 +1,191 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package pool
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/wkRonin/toolkit/logger"
+)
+
+func TestWorkerPool_Concurrency(t *testing.T) {
+	p := NewWorkerPool(3, WithQueueSize(100))
+	var running, maxRunning, finished atomic.Int32
+	for i := 0; i < 30; i++ {
+		err := p.Submit(context.Background(), func(ctx context.Context) {
+			cur := running.Add(1)
+			for {
+				old := maxRunning.Load()
+				if cur <= old || maxRunning.CompareAndSwap(old, cur) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			running.Add(-1)
+			finished.Add(1)
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Equal(t, int32(30), finished.Load())
+	assert.Equal(t, int32(3), maxRunning.Load())
+}
+
+func TestWorkerPool_Submit(t *testing.T) {
+	testCases := []struct {
+		name    string
+		opts    []Option
+		ctx     func() (context.Context, context.CancelFunc)
+		wantErr error
+	}{
+		{
+			name: "阻塞直到超时",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 50*time.Millisecond)
+			},
+			wantErr: context.DeadlineExceeded,
+		},
+		{
+			name: "直接失败",
+			opts: []Option{WithFailFast()},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			wantErr: ErrPoolFull,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := NewWorkerPool(1, append(tc.opts, WithQueueSize(1))...)
+			block := make(chan struct{})
+			// 一个执行中，一个排队中
+			for i := 0; i < 2; i++ {
+				require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) {
+					<-block
+				}))
+			}
+			ctx, cancel := tc.ctx()
+			defer cancel()
+			err := p.Submit(ctx, func(ctx context.Context) {})
+			assert.Equal(t, tc.wantErr, err)
+			close(block)
+			require.NoError(t, p.Shutdown(context.Background()))
+		})
+	}
+}
+
+func TestWorkerPool_Priority(t *testing.T) {
+	p := NewWorkerPool(1, WithQueueSize(10), WithPriority())
+	block, started := make(chan struct{}), make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) {
+		close(started)
+		<-block
+	}))
+	// 等 worker 被占住之后再提交，保证后面的任务都在排队
+	<-started
+	var res []int
+	for _, priority := range []int{1, 3, 2, 3} {
+		priority := priority
+		require.NoError(t, p.SubmitWithPriority(context.Background(), priority, func(ctx context.Context) {
+			res = append(res, priority)
+		}))
+	}
+	close(block)
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Equal(t, []int{3, 3, 2, 1}, res)
+}
+
+func TestWorkerPool_Panic(t *testing.T) {
+	l := &mockLogger{}
+	p := NewWorkerPool(1, WithLogger(l))
+	require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) {
+		panic("mock panic")
+	}))
+	var finished atomic.Bool
+	require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) {
+		finished.Store(true)
+	}))
+	require.NoError(t, p.Shutdown(context.Background()))
+	// panic 之后 worker 依然可以继续执行任务
+	assert.True(t, finished.Load())
+	require.Len(t, l.msgs, 1)
+	assert.Equal(t, "mock panic", l.msgs[0].Value)
+}
+
+func TestWorkerPool_Shutdown(t *testing.T) {
+	p := NewWorkerPool(1)
+	canceled := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		close(canceled)
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, p.Shutdown(ctx))
+	// 超时之后执行中的任务收到取消信号
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("任务没有收到取消信号")
+	}
+	assert.Equal(t, ErrPoolClosed, p.Submit(context.Background(), func(ctx context.Context) {}))
+	assert.Equal(t, ErrPoolClosed, p.Shutdown(context.Background()))
+}
+
+func TestWorkerPool_ShutdownDropQueued(t *testing.T) {
+	before := runtime.NumGoroutine()
+	p := NewWorkerPool(1, WithQueueSize(1))
+	exited := make(chan struct{})
+	require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		close(exited)
+	}))
+	var executed atomic.Bool
+	require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) {
+		executed.Store(true)
+	}))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, p.Shutdown(ctx))
+	<-exited
+	// 排队中的任务被丢弃，worker 退出之后不会留下等待任务的协程
+	for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+	assert.False(t, executed.Load())
+}
+
+type mockLogger struct {
+	logger.NopLogger
+	mutex sync.Mutex
+	msgs  []logger.Field
+}
+
+func (m *mockLogger) Error(msg string, args ...logger.Field) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.msgs = append(m.msgs, args[0])
+}
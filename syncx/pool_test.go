@@ -0,0 +1,49 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPool(t *testing.T) {
+	p := NewPool[*bytes.Buffer](func() *bytes.Buffer {
+		return &bytes.Buffer{}
+	}, WithReset(func(buf *bytes.Buffer) {
+		buf.Reset()
+	}))
+	buf := p.Get()
+	assert.NotNil(t, buf)
+	buf.WriteString("hello")
+	p.Put(buf)
+	// 放回之前已经被重置
+	assert.Equal(t, 0, buf.Len())
+	assert.Equal(t, 0, p.Get().Len())
+}
+
+func TestPool_WithoutReset(t *testing.T) {
+	cnt := 0
+	p := NewPool[int](func() int {
+		cnt++
+		return cnt
+	})
+	assert.Equal(t, 1, p.Get())
+	p.Put(10)
+}
@@ -0,0 +1,96 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import (
+	"context"
+	"hash/fnv"
+)
+
+// chanMutex 用 channel 实现的互斥锁，加锁可以被 ctx 打断
+type chanMutex chan struct{}
+
+func newChanMutex() chanMutex {
+	return make(chanMutex, 1)
+}
+
+func (m chanMutex) lock() {
+	m <- struct{}{}
+}
+
+func (m chanMutex) tryLock(ctx context.Context) error {
+	// 先尝试一次，避免 ctx 已经结束时 select 随机选中 ctx.Done()
+	select {
+	case m <- struct{}{}:
+		return nil
+	default:
+	}
+	select {
+	case m <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m chanMutex) unlock() {
+	select {
+	case <-m:
+	default:
+		panic("syncx: unlock of unlocked mutex")
+	}
+}
+
+// SegmentKeysLock 分段锁，key 按照哈希值分配到固定数量的锁上
+// 同一个 key 的操作串行执行，不同的 key 大概率可以并行
+// 内存占用固定，代价是不同的 key 可能共用一把锁
+type SegmentKeysLock struct {
+	locks []chanMutex
+}
+
+// NewSegmentKeysLock 创建分段锁，size 为锁的数量
+func NewSegmentKeysLock(size uint32) *SegmentKeysLock {
+	if size == 0 {
+		size = 1
+	}
+	locks := make([]chanMutex, size)
+	for i := range locks {
+		locks[i] = newChanMutex()
+	}
+	return &SegmentKeysLock{
+		locks: locks,
+	}
+}
+
+func (s *SegmentKeysLock) get(key string) chanMutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return s.locks[h.Sum32()%uint32(len(s.locks))]
+}
+
+func (s *SegmentKeysLock) Lock(key string) {
+	s.get(key).lock()
+}
+
+// TryLock 加锁，直到加锁成功或者 ctx 结束
+func (s *SegmentKeysLock) TryLock(ctx context.Context, key string) error {
+	return s.get(key).tryLock(ctx)
+}
+
+func (s *SegmentKeysLock) Unlock(key string) {
+	s.get(key).unlock()
+}
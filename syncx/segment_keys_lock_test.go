@@ -0,0 +1,75 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegmentKeysLock(t *testing.T) {
+	l := NewSegmentKeysLock(0)
+	cnt := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Lock("user-1")
+				cnt++
+				l.Unlock("user-1")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1000, cnt)
+}
+
+func TestSegmentKeysLock_TryLock(t *testing.T) {
+	l := NewSegmentKeysLock(1024)
+	l.Lock("a")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l.TryLock(ctx, "a"))
+
+	// 已经结束的 ctx 依然可以拿到空闲的锁
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	key := "b"
+	for l.get(key) == l.get("a") {
+		key += "b"
+	}
+	assert.NoError(t, l.TryLock(canceled, key))
+	l.Unlock(key)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		l.Unlock("a")
+	}()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, l.TryLock(ctx, "a"))
+	l.Unlock("a")
+	assert.Panics(t, func() {
+		l.Unlock("a")
+	})
+}
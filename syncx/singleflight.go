@@ -0,0 +1,194 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Result DoChan 返回的结果
+type Result[V any] struct {
+	Val V
+	Err error
+	// Shared 结果是否来自其他调用方发起的执行
+	Shared bool
+}
+
+type call[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+	// waiters 等待结果的调用方数量，DoContext 的调用方全部放弃之后会取消执行
+	waiters int
+	cancel  context.CancelFunc
+}
+
+// SingleFlight 泛型的 singleflight，同一个 key 同一时间只会执行一次，其他调用方共享结果
+// window > 0 时，执行成功之后的 window 时间内到达的调用方也直接复用这个结果，适合防止缓存击穿
+// 执行失败的结果不会被保留
+// 零值可以直接使用，此时 window 为 0
+type SingleFlight[K comparable, V any] struct {
+	mutex  sync.Mutex
+	calls  map[K]*call[V]
+	window time.Duration
+}
+
+// NewSingleFlight 创建 SingleFlight，window 为执行完成之后结果继续共享的时长
+func NewSingleFlight[K comparable, V any](window time.Duration) *SingleFlight[K, V] {
+	return &SingleFlight[K, V]{
+		window: window,
+	}
+}
+
+// join 返回 key 对应的执行，不存在时创建一个新的，调用方需要持有锁
+func (s *SingleFlight[K, V]) join(key K) (c *call[V], shared bool) {
+	if s.calls == nil {
+		s.calls = make(map[K]*call[V])
+	}
+	c, shared = s.calls[key]
+	if !shared {
+		c = &call[V]{done: make(chan struct{})}
+		s.calls[key] = c
+	}
+	c.waiters++
+	return c, shared
+}
+
+// Do 执行 fn 并返回结果，同一个 key 正在执行或者还在共享窗口内时，等待并复用已有的结果
+// fn panic 时所有等待的调用方都会拿到 error，执行 fn 的调用方会继续 panic
+func (s *SingleFlight[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	s.mutex.Lock()
+	c, shared := s.join(key)
+	s.mutex.Unlock()
+	if shared {
+		<-c.done
+		return c.val, c.err, true
+	}
+	if r := s.doCall(key, c, fn); r != nil {
+		panic(r)
+	}
+	return c.val, c.err, false
+}
+
+// DoChan 和 Do 一样，但是不阻塞，结果从返回的 channel 中读取
+// fn 在新的 goroutine 里执行，panic 只会变成 error 返回，不会让整个进程崩溃
+func (s *SingleFlight[K, V]) DoChan(key K, fn func() (V, error)) <-chan Result[V] {
+	ch := make(chan Result[V], 1)
+	s.mutex.Lock()
+	c, shared := s.join(key)
+	s.mutex.Unlock()
+	go func() {
+		if !shared {
+			_ = s.doCall(key, c, fn)
+		}
+		<-c.done
+		ch <- Result[V]{Val: c.val, Err: c.err, Shared: shared}
+	}()
+	return ch
+}
+
+// DoContext 在新的 goroutine 里执行 fn，调用方在 ctx 结束时直接返回 ctx.Err()，不影响其他调用方
+// 传给 fn 的 ctx 带有第一个调用方 ctx 中的值，但不会随着它取消，只有所有的调用方都放弃之后才会被取消
+// fn panic 的时候和 DoChan 一样，所有等待的调用方都拿到 error
+func (s *SingleFlight[K, V]) DoContext(ctx context.Context, key K,
+	fn func(ctx context.Context) (V, error)) (v V, err error, shared bool) {
+	s.mutex.Lock()
+	c, shared := s.join(key)
+	if !shared {
+		fnCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c.cancel = cancel
+		go func() {
+			defer cancel()
+			_ = s.doCall(key, c, func() (V, error) {
+				return fn(fnCtx)
+			})
+		}()
+	}
+	s.mutex.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err, shared
+	case <-ctx.Done():
+		s.leave(key, c)
+		return v, ctx.Err(), shared
+	}
+}
+
+func (s *SingleFlight[K, V]) leave(key K, c *call[V]) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	c.waiters--
+	if c.waiters > 0 || c.cancel == nil {
+		return
+	}
+	select {
+	case <-c.done:
+		return
+	default:
+	}
+	c.cancel()
+	// 已经取消的执行不能再被新的调用方复用
+	if s.calls[key] == c {
+		delete(s.calls, key)
+	}
+}
+
+// doCall 执行 fn 并通知等待的调用方，fn panic 时返回 panic 的值，由调用方决定是否继续 panic
+func (s *SingleFlight[K, V]) doCall(key K, c *call[V], fn func() (V, error)) (recovered any) {
+	defer func() {
+		if r := recover(); r != nil {
+			recovered = r
+			c.err = fmt.Errorf("syncx: singleflight 执行 panic: %v", r)
+			s.finish(key, c)
+		}
+	}()
+	c.val, c.err = fn()
+	s.finish(key, c)
+	return nil
+}
+
+func (s *SingleFlight[K, V]) finish(key K, c *call[V]) {
+	close(c.done)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.calls[key] != c {
+		// 已经被 Forget 或者取消了
+		return
+	}
+	if s.window <= 0 || c.err != nil {
+		delete(s.calls, key)
+		return
+	}
+	time.AfterFunc(s.window, func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if s.calls[key] == c {
+			delete(s.calls, key)
+		}
+	})
+}
+
+// Forget 丢弃 key 正在执行或者还在共享的结果，之后的调用会重新执行
+func (s *SingleFlight[K, V]) Forget(key K) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.calls, key)
+}
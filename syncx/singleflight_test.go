@@ -0,0 +1,252 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package syncx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingleFlight_Do(t *testing.T) {
+	var s SingleFlight[string, int]
+	var cnt atomic.Int32
+	block := make(chan struct{})
+	var wg sync.WaitGroup
+	var sharedCnt atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err, shared := s.Do("key", func() (int, error) {
+				cnt.Add(1)
+				<-block
+				return 1, nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, 1, v)
+			if shared {
+				sharedCnt.Add(1)
+			}
+		}()
+	}
+	// 等所有的调用方都加入
+	require.Eventually(t, func() bool {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return s.calls["key"] != nil && s.calls["key"].waiters == 10
+	}, time.Second, time.Millisecond)
+	close(block)
+	wg.Wait()
+	assert.Equal(t, int32(1), cnt.Load())
+	assert.Equal(t, int32(9), sharedCnt.Load())
+
+	// 没有共享窗口，执行完之后立刻删除
+	v, _, shared := s.Do("key", func() (int, error) {
+		return 2, nil
+	})
+	assert.Equal(t, 2, v)
+	assert.False(t, shared)
+}
+
+func TestSingleFlight_Window(t *testing.T) {
+	s := NewSingleFlight[string, int](100 * time.Millisecond)
+	v, err, shared := s.Do("key", func() (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.False(t, shared)
+
+	// 窗口内复用结果
+	v, _, shared = s.Do("key", func() (int, error) {
+		return 2, nil
+	})
+	assert.Equal(t, 1, v)
+	assert.True(t, shared)
+
+	// 窗口过后重新执行
+	time.Sleep(200 * time.Millisecond)
+	v, _, shared = s.Do("key", func() (int, error) {
+		return 3, nil
+	})
+	assert.Equal(t, 3, v)
+	assert.False(t, shared)
+
+	// Forget 之后重新执行
+	s.Forget("key")
+	v, _, _ = s.Do("key", func() (int, error) {
+		return 4, nil
+	})
+	assert.Equal(t, 4, v)
+
+	// 失败的结果不保留
+	_, err, _ = s.Do("err", func() (int, error) {
+		return 0, errors.New("mock error")
+	})
+	assert.Equal(t, errors.New("mock error"), err)
+	v, err, shared = s.Do("err", func() (int, error) {
+		return 5, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 5, v)
+	assert.False(t, shared)
+}
+
+func TestSingleFlight_DoChan(t *testing.T) {
+	var s SingleFlight[string, int]
+	block := make(chan struct{})
+	ch1 := s.DoChan("key", func() (int, error) {
+		<-block
+		return 1, nil
+	})
+	ch2 := s.DoChan("key", func() (int, error) {
+		return 2, nil
+	})
+	close(block)
+	res1, res2 := <-ch1, <-ch2
+	assert.Equal(t, Result[int]{Val: 1}, res1)
+	assert.Equal(t, Result[int]{Val: 1, Shared: true}, res2)
+}
+
+type ctxKey struct{}
+
+func TestSingleFlight_DoContext(t *testing.T) {
+	var s SingleFlight[string, string]
+	block := make(chan struct{})
+	ctx1, cancel1 := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "value"))
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err, _ := s.DoContext(ctx1, "key", func(ctx context.Context) (string, error) {
+			<-block
+			// 第一个调用方取消不影响执行
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			return ctx.Value(ctxKey{}).(string), nil
+		})
+		assert.Equal(t, context.Canceled, err)
+	}()
+	require.Eventually(t, func() bool {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return s.calls["key"] != nil
+	}, time.Second, time.Millisecond)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		v, err, shared := s.DoContext(context.Background(), "key", func(ctx context.Context) (string, error) {
+			return "other", nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "value", v)
+		assert.True(t, shared)
+	}()
+	require.Eventually(t, func() bool {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return s.calls["key"].waiters == 2
+	}, time.Second, time.Millisecond)
+	cancel1()
+	time.Sleep(10 * time.Millisecond)
+	close(block)
+	wg.Wait()
+}
+
+func TestSingleFlight_DoContextAllCanceled(t *testing.T) {
+	var s SingleFlight[string, int]
+	canceled := make(chan struct{})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err, _ := s.DoContext(ctx, "key", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		close(canceled)
+		return 0, ctx.Err()
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	// 所有的调用方都放弃之后，执行被取消
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("执行没有被取消")
+	}
+	v, err, shared := s.DoContext(context.Background(), "key", func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+	assert.False(t, shared)
+}
+
+func TestSingleFlight_Panic(t *testing.T) {
+	var s SingleFlight[string, int]
+	block := make(chan struct{})
+	ch := make(chan error, 1)
+	go func() {
+		defer func() {
+			_ = recover()
+		}()
+		_, _, _ = s.Do("key", func() (int, error) {
+			<-block
+			panic("mock panic")
+		})
+	}()
+	require.Eventually(t, func() bool {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return s.calls["key"] != nil
+	}, time.Second, time.Millisecond)
+	go func() {
+		_, err, _ := s.Do("key", func() (int, error) {
+			return 1, nil
+		})
+		ch <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(block)
+	assert.EqualError(t, <-ch, "syncx: singleflight 执行 panic: mock panic")
+}
+
+func TestSingleFlight_PanicInBackground(t *testing.T) {
+	var s SingleFlight[string, int]
+	_, err, _ := s.DoContext(context.Background(), "key", func(ctx context.Context) (int, error) {
+		panic("mock panic")
+	})
+	// 后台 goroutine 里的 panic 不会让进程崩溃
+	assert.EqualError(t, err, "syncx: singleflight 执行 panic: mock panic")
+
+	res := <-s.DoChan("key", func() (int, error) {
+		panic("mock panic")
+	})
+	assert.EqualError(t, res.Err, "syncx: singleflight 执行 panic: mock panic")
+
+	// 失败的结果不会被保留，之后的调用重新执行
+	v, err, _ := s.Do("key", func() (int, error) {
+		return 1, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
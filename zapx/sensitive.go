@@ -16,7 +16,13 @@
 
 package zapx
 
-import "go.uber.org/zap"
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/wkRonin/toolkit/containerx/trie"
+)
 
 func MaskPhone(key string, value string) zap.Field {
 	value = value[:3] + "****" + value[7:]
@@ -25,3 +31,25 @@ func MaskPhone(key string, value string) zap.Field {
 		String: value,
 	}
 }
+
+// MaskSecrets 把 value 中命中 secrets 的部分替换成 *，secrets 为 nil 时原样返回
+func MaskSecrets(key string, value string, secrets *trie.ACTrie) zap.Field {
+	if secrets != nil {
+		value = secrets.Replace(value, '*')
+	}
+	return zap.Field{
+		Key:    key,
+		String: value,
+	}
+}
+
+// MaskError 把 err 的错误信息中命中 secrets 的部分替换成 *
+func MaskError(key string, err error, secrets *trie.ACTrie) zap.Field {
+	// fmt.Sprint 可以处理 nil 指针和 Error 方法 panic 的情况
+	return MaskSecrets(key, fmt.Sprint(err), secrets)
+}
+
+// MaskStringer 把 val.String() 中命中 secrets 的部分替换成 *
+func MaskStringer(key string, val fmt.Stringer, secrets *trie.ACTrie) zap.Field {
+	return MaskSecrets(key, fmt.Sprint(val), secrets)
+}
@@ -0,0 +1,103 @@
+/*
+ *    Copyright 2023 wkRonin
+ *
+ *    Licensed under the Apache License, Version 2.0 (the "License");
+ *    you may not use this file except in compliance with the License.
+ *    You may obtain a copy of the License at
+ *
+ *        http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *    Unless required by applicable law or agreed to in writing, software
+ *    distributed under the License is distributed on an "AS IS" BASIS,
+ *    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *    See the License for the specific language governing permissions and
+ *    limitations under the License.
+ */
+
+package zapx
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/wkRonin/toolkit/containerx/trie"
+)
+
+type user struct {
+	password string
+}
+
+func (u *user) String() string {
+	return "password=" + u.password
+}
+
+func TestMaskPhone(t *testing.T) {
+	assert.Equal(t, "138****1111", MaskPhone("phone", "13800001111").String)
+}
+
+func TestMaskSecrets(t *testing.T) {
+	secrets := trie.NewACTrie([]string{"abc123"})
+	testCases := []struct {
+		name string
+		got  func() string
+		want string
+	}{
+		{
+			name: "字符串",
+			got: func() string {
+				return MaskSecrets("token", "token=abc123", secrets).String
+			},
+			want: "token=******",
+		},
+		{
+			name: "error",
+			got: func() string {
+				return MaskError("error", errors.New("invalid abc123"), secrets).String
+			},
+			want: "invalid ******",
+		},
+		{
+			name: "Stringer",
+			got: func() string {
+				return MaskStringer("user", &user{password: "abc123"}, secrets).String
+			},
+			want: "password=******",
+		},
+		{
+			name: "nil 指针的 Stringer",
+			got: func() string {
+				var u *user
+				return MaskStringer("user", u, secrets).String
+			},
+			want: "<nil>",
+		},
+		{
+			name: "没有 secrets",
+			got: func() string {
+				return MaskSecrets("token", "token=abc123", nil).String
+			},
+			want: "token=abc123",
+		},
+		{
+			name: "没有 secrets 的 error",
+			got: func() string {
+				return MaskError("error", errors.New("invalid abc123"), nil).String
+			},
+			want: "invalid abc123",
+		},
+		{
+			name: "没有 secrets 的 Stringer",
+			got: func() string {
+				return MaskStringer("user", &user{password: "abc123"}, nil).String
+			},
+			want: "password=abc123",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.got())
+		})
+	}
+}